@@ -0,0 +1,84 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OutputLayout returns path of schema file for root type relative to output directory
+type OutputLayout func(packagePath, typeName string) string
+
+// FlatLayout puts all schema files directly into output directory, e.g. User.json
+func FlatLayout(_, typeName string) string {
+	return typeName + ".json"
+}
+
+// PackagePathLayout mirrors full package import path, e.g. github.com/x/y/api/User.json
+func PackagePathLayout(packagePath, typeName string) string {
+	return path.Join(packagePath, typeName+".json")
+}
+
+// RelativePackageLayout mirrors package import path relative to module path,
+// e.g. api/User.json for package github.com/x/y/api and module github.com/x/y
+func RelativePackageLayout(modulePath string) OutputLayout {
+	return func(packagePath, typeName string) string {
+		relativePath := strings.TrimPrefix(strings.TrimPrefix(packagePath, modulePath), "/")
+		return path.Join(relativePath, typeName+".json")
+	}
+}
+
+// FromPatternsToJsonSchemaFiles generates schema file for every exported struct of packages matched
+// by patterns (e.g. ./api/...) that matches filter. Nil filter selects all exported structs and
+// nil layout means PackagePathLayout. Written file paths are returned.
+func FromPatternsToJsonSchemaFiles(patterns []string, filter *parser.TypeFilter,
+	outputDir string, layout OutputLayout) ([]string, error) {
+	generator := DefaultGenerator()
+	generator.Parser = parser.NewAstPatternParser(patterns, filter)
+	return generator.GenerateFiles(outputDir, layout)
+}
+
+// GenerateFiles generates schema for every root type parsed by Parser and writes them to outputDir
+// using layout. Nil layout means PackagePathLayout. Written file paths are returned.
+func (g *SchemaGenerator) GenerateFiles(outputDir string, layout OutputLayout) ([]string, error) {
+	if layout == nil {
+		layout = PackagePathLayout
+	}
+
+	jsonSchemas, err := g.GenerateEach()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(jsonSchemas))
+	for id := range jsonSchemas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	files := make([]string, 0, len(ids))
+	for _, id := range ids {
+		// Type ID has form <package>#<type name>
+		separator := strings.LastIndexByte(id, '#')
+		packagePath, typeName := id[:separator], id[separator+1:]
+
+		filename := filepath.Join(outputDir, filepath.FromSlash(layout(packagePath, typeName)))
+		if err = os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			return nil, fmt.Errorf("create directory for %s: %w", typeName, err)
+		}
+		data, err := json.MarshalIndent(jsonSchemas[id], "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", typeName, err)
+		}
+		if err = os.WriteFile(filename, data, 0o644); err != nil {
+			return nil, fmt.Errorf("write %s: %w", typeName, err)
+		}
+		files = append(files, filename)
+	}
+	return files, nil
+}
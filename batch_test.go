@@ -0,0 +1,29 @@
+package jsonschema
+
+import (
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateFilesForPatterns(t *testing.T) {
+	outputDir := t.TempDir()
+	filter := &parser.TypeFilter{Marker: "jsonschema:generate"}
+	files, err := FromPatternsToJsonSchemaFiles([]string{"github.com/paulrozhkin/jsonschema/tests/batch/..."},
+		filter, outputDir, RelativePackageLayout("github.com/paulrozhkin/jsonschema/tests/batch"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(outputDir, "api", "User.json"),
+		filepath.Join(outputDir, "api", "v1", "Order.json"),
+	}, files)
+	require.FileExists(t, files[0])
+	require.FileExists(t, files[1])
+}
+
+func TestOutputLayouts(t *testing.T) {
+	const packagePath = "github.com/x/y/api"
+	require.Equal(t, "User.json", FlatLayout(packagePath, "User"))
+	require.Equal(t, "github.com/x/y/api/User.json", PackagePathLayout(packagePath, "User"))
+	require.Equal(t, "api/User.json", RelativePackageLayout("github.com/x/y")(packagePath, "User"))
+}
@@ -0,0 +1,231 @@
+// Command jsonschema generates JSON Schema from Go types and Go types from JSON Schema.
+//
+// Usage:
+//
+//	jsonschema generate -package <import path> -type <type name> [-format json|yaml] [-inline] [-out schema.json]
+//	jsonschema codegen -package <package name> [-root <type name>] [-out types.go] schema.json
+//	jsonschema bundle [-out bundle.json] schema.json
+//	jsonschema diff [-format text|json] old.json new.json
+//	jsonschema compat [-level BACKWARD] v1.json v2.json ... new.json
+//	jsonschema fake [-seed 1] [-count 1] schema.json
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/codegen"
+	"github.com/paulrozhkin/jsonschema/pkg/compatibility"
+	"github.com/paulrozhkin/jsonschema/pkg/diff"
+	"github.com/paulrozhkin/jsonschema/pkg/fake"
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"github.com/paulrozhkin/jsonschema/pkg/transform"
+	"os"
+)
+
+const usage = `usage:
+	jsonschema generate -package <import path> -type <type name> [-format json|yaml] [-inline] [-out schema.json]
+	jsonschema codegen -package <package name> [-root <type name>] [-out types.go] schema.json
+	jsonschema bundle [-out bundle.json] schema.json
+	jsonschema diff [-format text|json] old.json new.json
+	jsonschema compat [-level BACKWARD] v1.json v2.json ... new.json
+	jsonschema fake [-seed 1] [-count 1] schema.json
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "codegen":
+		err = runCodegen(os.Args[2:])
+	case "bundle":
+		err = runBundle(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "compat":
+		err = runCompat(os.Args[2:])
+	case "fake":
+		err = runFake(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "jsonschema:", err)
+		os.Exit(1)
+	}
+}
+
+// runGenerate generates schema of Go type
+func runGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	packageName := flags.String("package", ".", "import path or directory of package")
+	typeName := flags.String("type", "", "name of root type")
+	format := flags.String("format", string(jsonschema.FormatJSON), "output format: json or yaml")
+	inline := flags.Bool("inline", false, "replace references to definitions by their schemas")
+	out := flags.String("out", "", "output file, standard output by default")
+	_ = flags.Parse(args)
+	if *typeName == "" {
+		return fmt.Errorf("flag -type is required")
+	}
+
+	generator := jsonschema.DefaultGenerator()
+	generator.Parser = parser.NewAstParser(*typeName, *packageName)
+	if *inline {
+		generator.AfterConvert = transform.InlineRefs
+	}
+	if err := generator.Generate(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := generator.Encode(&buf, jsonschema.Format(*format)); err != nil {
+		return err
+	}
+	if jsonschema.Format(*format) == jsonschema.FormatJSON {
+		buf.WriteByte('\n')
+	}
+	return writeOutput(*out, buf.Bytes())
+}
+
+// runCodegen generates Go types of schema
+func runCodegen(args []string) error {
+	flags := flag.NewFlagSet("codegen", flag.ExitOnError)
+	packageName := flags.String("package", "", "package name of generated code")
+	rootName := flags.String("root", "", "type name of root schema")
+	out := flags.String("out", "", "output file, standard output by default")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("single schema file is required")
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	source, err := codegen.GenerateFromJSON(data, codegen.Config{PackageName: *packageName, RootName: *rootName})
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, source)
+}
+
+// runBundle bundles schema file with files it references into single schema
+func runBundle(args []string) error {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	out := flags.String("out", "", "output file, standard output by default")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("single schema file is required")
+	}
+
+	schema, err := transform.BundleFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, append(data, '\n'))
+}
+
+// runDiff reports changes of new schema file against old one. Error is returned, if any change is breaking.
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "text", "report format: text or json")
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 {
+		return fmt.Errorf("old and new schema files are required")
+	}
+
+	oldData, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	newData, err := os.ReadFile(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	report, err := diff.CompareJSON(oldData, newData)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "text":
+		_, err = fmt.Fprint(os.Stdout, report.String())
+	case "json":
+		var data []byte
+		if data, err = json.MarshalIndent(report, "", "  "); err == nil {
+			_, err = os.Stdout.Write(append(data, '\n'))
+		}
+	default:
+		return fmt.Errorf("unknown format %s", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if report.Breaking() {
+		return fmt.Errorf("breaking changes found")
+	}
+	return nil
+}
+
+// runCompat checks compatibility of the last schema file with previous ones
+func runCompat(args []string) error {
+	flags := flag.NewFlagSet("compat", flag.ExitOnError)
+	levelName := flags.String("level", string(compatibility.Backward), "compatibility level, e.g. FULL_TRANSITIVE")
+	_ = flags.Parse(args)
+	if flags.NArg() < 2 {
+		return fmt.Errorf("previous and new schema files are required")
+	}
+
+	level, err := compatibility.ParseLevel(*levelName)
+	if err != nil {
+		return err
+	}
+	files := flags.Args()
+	return compatibility.CheckFiles(level, files[:len(files)-1], files[len(files)-1])
+}
+
+// runFake writes instances of schema file, one JSON document per line
+func runFake(args []string) error {
+	flags := flag.NewFlagSet("fake", flag.ExitOnError)
+	seed := flags.Int64("seed", 1, "seed of generated instances")
+	count := flags.Int("count", 1, "count of instances")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("single schema file is required")
+	}
+
+	schema, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	generator := fake.New(*seed)
+	for i := 0; i < *count; i++ {
+		instance, err := generator.GenerateJSON(schema)
+		if err != nil {
+			return err
+		}
+		if _, err = os.Stdout.Write(append(instance, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeOutput(filename string, data []byte) error {
+	if filename != "" {
+		return os.WriteFile(filename, data, 0o644)
+	}
+	_, err := os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,37 @@
+package jsonschema
+
+import (
+	"github.com/paulrozhkin/jsonschema/pkg/compatibility"
+	"os"
+)
+
+// CheckCompatibility generates schema of obj and checks its compatibility with history of schema files
+// of the type ordered from the oldest to the latest, see compatibility.Check
+func CheckCompatibility(obj any, level compatibility.Level, history ...string) error {
+	generator, err := FromTypeToJsonSchema(obj)
+	if err != nil {
+		return err
+	}
+	return generator.CheckCompatibility(level, history...)
+}
+
+// CheckCompatibility checks compatibility of generated schema with history of schema files ordered
+// from the oldest to the latest
+func (g *SchemaGenerator) CheckCompatibility(level compatibility.Level, history ...string) error {
+	if g.jsonSchema == nil {
+		return ErrSchemaNotGenerated
+	}
+	schema, err := g.ToJson()
+	if err != nil {
+		return err
+	}
+	versions := make([][]byte, 0, len(history))
+	for _, filename := range history {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		versions = append(versions, data)
+	}
+	return compatibility.Check(level, versions, schema)
+}
@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"regexp"
+	"slices"
+)
+
+// Format is output format of schema
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// ToYAML returns schema as YAML with the same key order as ToJson
+func (g *SchemaGenerator) ToYAML() ([]byte, error) {
+	jsonData, err := g.ToJson()
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(jsonData)
+}
+
+// Encode writes schema to w in format
+func (g *SchemaGenerator) Encode(w io.Writer, format Format) error {
+	var data []byte
+	var err error
+	switch format {
+	case FormatJSON:
+		data, err = g.ToJson()
+	case FormatYAML:
+		data, err = g.ToYAML()
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// jsonToYAML converts JSON document to YAML keeping order of keys. JSON is valid YAML, so document is
+// parsed as YAML node and written back in block style.
+func jsonToYAML(jsonData []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonData, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle removes flow style and quotes of JSON. Encoder quotes strings, only if YAML 1.2 requires it,
+// so strings, that YAML 1.1 reads as booleans, nulls or numbers, are double-quoted, e.g. "on" or "0777".
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && isYAML11Scalar(node.Value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// yaml11Scalars are booleans and nulls of YAML 1.1
+var yaml11Scalars = []string{"y", "Y", "yes", "Yes", "YES", "n", "N", "no", "No", "NO", "true", "True", "TRUE",
+	"false", "False", "FALSE", "on", "On", "ON", "off", "Off", "OFF", "", "~", "null", "Null", "NULL"}
+
+// yaml11Number matches integers and floats of YAML 1.1: binary, octal, hexadecimal and sexagesimal numbers,
+// numbers with underscores, infinity and NaN
+var yaml11Number = regexp.MustCompile(`^(?:[-+]?0b[0-1_]+|[-+]?0[0-7_]+|[-+]?(?:0|[1-9][0-9_]*)|` +
+	`[-+]?0x[0-9a-fA-F_]+|[-+]?[1-9][0-9_]*(?::[0-5]?[0-9])+|` +
+	`[-+]?(?:[0-9][0-9_]*)?\.[0-9_]*(?:[eE][-+]?[0-9]+)?|[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+\.[0-9_]*|` +
+	`[-+]?[0-9][0-9_]*[eE][-+]?[0-9]+|[-+]?\.(?:inf|Inf|INF)|\.(?:nan|NaN|NAN))$`)
+
+// isYAML11Scalar reports whether YAML 1.1 resolves plain scalar as boolean, null or number
+func isYAML11Scalar(value string) bool {
+	return slices.Contains(yaml11Scalars, value) || yaml11Number.MatchString(value)
+}
@@ -0,0 +1,105 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"os"
+	"testing"
+)
+
+func TestToYAML(t *testing.T) {
+	generator, err := FromTypeToJsonSchema(base.Settings{})
+	require.NoError(t, err)
+
+	actualYAML, err := generator.ToYAML()
+	require.NoError(t, err)
+	expectedYAML, err := os.ReadFile("./tests/output/settings.yaml")
+	require.NoError(t, err)
+	require.Equal(t, string(expectedYAML), string(actualYAML))
+
+	// YAML contains the same document as JSON
+	var fromYAML, fromJSON any
+	require.NoError(t, yaml.Unmarshal(actualYAML, &fromYAML))
+	actualJSON, err := generator.ToJson()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(actualJSON, &fromJSON))
+	require.EqualValues(t, fromJSON, normalizeYAML(fromYAML))
+}
+
+func TestEncode(t *testing.T) {
+	generator, err := FromTypeToJsonSchema(base.Settings{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, generator.Encode(&buf, FormatJSON))
+	expectedJSON, err := generator.ToJson()
+	require.NoError(t, err)
+	require.Equal(t, expectedJSON, buf.Bytes())
+
+	buf.Reset()
+	require.NoError(t, generator.Encode(&buf, FormatYAML))
+	expectedYAML, err := generator.ToYAML()
+	require.NoError(t, err)
+	require.Equal(t, expectedYAML, buf.Bytes())
+
+	require.ErrorContains(t, generator.Encode(&buf, "toml"), "unknown format toml")
+}
+
+func TestToYAMLQuotesYAML11Scalars(t *testing.T) {
+	values := []string{"on", "Off", "y", "N", "yes", "NO", "true", "null", "~", "", "0777", "0x1F", "0b101",
+		"1_000", "1:20", "1:20.5", ".5", "1e3", "+1", ".inf", ".NaN"}
+	data, err := json.Marshal(map[string]any{"enum": values, "y": "plain"})
+	require.NoError(t, err)
+	actual, err := jsonToYAML(data)
+	require.NoError(t, err)
+	require.Equal(t, `enum:
+  - "on"
+  - "Off"
+  - "y"
+  - "N"
+  - "yes"
+  - "NO"
+  - "true"
+  - "null"
+  - "~"
+  - ""
+  - "0777"
+  - "0x1F"
+  - "0b101"
+  - "1_000"
+  - "1:20"
+  - "1:20.5"
+  - ".5"
+  - "1e3"
+  - "+1"
+  - ".inf"
+  - ".NaN"
+"y": plain
+`, string(actual))
+
+	var fromYAML struct {
+		Enum []string `yaml:"enum"`
+	}
+	require.NoError(t, yaml.Unmarshal(actual, &fromYAML))
+	require.Equal(t, values, fromYAML.Enum)
+}
+
+// normalizeYAML converts YAML integers to float64 like encoding/json
+func normalizeYAML(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			value[key] = normalizeYAML(item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = normalizeYAML(item)
+		}
+	case int:
+		return float64(value)
+	}
+	return value
+}
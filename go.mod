@@ -6,11 +6,11 @@ require (
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/mod v0.18.0
 	golang.org/x/tools v0.22.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/sync v0.10.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
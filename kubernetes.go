@@ -0,0 +1,52 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema/pkg/kubernetes"
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+)
+
+// FromTypeToCRD generates CustomResourceDefinition YAML of custom resource type. Kind is name of the type,
+// if config has no Kind.
+func FromTypeToCRD(obj any, config kubernetes.Config) ([]byte, error) {
+	generator := DefaultGenerator()
+	generator.Parser = parser.NewReflectParser(obj)
+	return generator.GenerateCRD(config)
+}
+
+// GenerateCRD generates CustomResourceDefinition YAML of root type parsed by Parser with structural schema,
+// see kubernetes.StructuralSchema
+func (g *SchemaGenerator) GenerateCRD(config kubernetes.Config) ([]byte, error) {
+	metadata, err := g.parse()
+	if err != nil {
+		return nil, err
+	}
+	multiConverter, err := g.multiConverter()
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := multiConverter.ConvertBundle(g.Config, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err = g.afterConvert(bundle); err != nil {
+		return nil, err
+	}
+
+	schema, err := kubernetes.StructuralSchema(bundle, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if config.Kind == "" {
+		config.Kind = metadata.Root.TypeName
+	}
+	crd, err := kubernetes.NewCustomResourceDefinition(config, schema)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(crd)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
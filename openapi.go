@@ -0,0 +1,24 @@
+package jsonschema
+
+import (
+	"github.com/paulrozhkin/jsonschema/pkg/openapi"
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+)
+
+// FromPackageToOpenAPIComponents generates OpenAPI component schema for every type of package.
+// If typeNames is empty, all exported structs of the package are used.
+func FromPackageToOpenAPIComponents(packageName string, version openapi.Version,
+	typeNames ...string) (*openapi.Components, error) {
+	generator := DefaultGenerator()
+	generator.Parser = parser.NewAstParserForTypes(typeNames, packageName)
+	return generator.GenerateOpenAPIComponents(version)
+}
+
+// GenerateOpenAPIComponents generates OpenAPI component schema for every type parsed by Parser.
+// Types reference each other by #/components/schemas/<type name>.
+func (g *SchemaGenerator) GenerateOpenAPIComponents(version openapi.Version) (*openapi.Components, error) {
+	if err := g.GenerateBundle(); err != nil {
+		return nil, err
+	}
+	return openapi.FromBundle(g.jsonSchema, version)
+}
@@ -0,0 +1,451 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+var ErrPackageNameNotFound = errors.New("package name of generated code not found")
+
+// Config configures generated code
+type Config struct {
+	// PackageName is package of generated code
+	PackageName string
+	// RootName is type name of root schema. Title or last segment of $id is used by default.
+	RootName string
+}
+
+// goType is a named type of generated code
+type goType struct {
+	name        string
+	description *string
+	schema      entity.DataType
+}
+
+type generator struct {
+	config Config
+	defs   *entity.OrderedMap[entity.DataType]
+	// defNames are type names of $defs
+	defNames map[string]string
+	// names contains all declared type names
+	names map[string]bool
+	types []*goType
+	buf   bytes.Buffer
+}
+
+// GenerateFromJSON generates Go types from JSON Schema document
+func GenerateFromJSON(data []byte, config Config) ([]byte, error) {
+	schema := new(entity.JSONSchema)
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("unmarshal schema: %w", err)
+	}
+	return Generate(schema, config)
+}
+
+// Generate generates Go types from schema. Root object becomes struct with RootName, every schema
+// of $defs becomes named type. Required properties are values, other properties have omitempty and are pointers
+// unless they are slices, maps or any, so generated types produce the same schema with ReflectParser.
+// Fields and types are in order of properties and $defs.
+func Generate(schema *entity.JSONSchema, config Config) ([]byte, error) {
+	if config.PackageName == "" {
+		return nil, ErrPackageNameNotFound
+	}
+	g := &generator{
+		config:   config,
+		defs:     schema.Defs,
+		defNames: make(map[string]string),
+		names:    make(map[string]bool),
+	}
+
+	// Root is declared first, so it gets its name without suffix
+	isRootObject := schema.Properties.Len() > 0 || schema.IsType(entity.JSONSchemaObject)
+	if isRootObject {
+		g.declare(rootName(schema, config), schema.Description, schema)
+	}
+	for _, name := range schema.Defs.Keys() {
+		g.defNames[name] = g.uniqueName(exportedName(name))
+	}
+	for _, name := range schema.Defs.Keys() {
+		def, _ := schema.Defs.Get(name)
+		g.types = append(g.types, &goType{name: g.defNames[name], schema: def})
+	}
+
+	g.buf.WriteString("// Code generated by jsonschema codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&g.buf, "package %s\n", config.PackageName)
+	// Types are appended while generating, when inline objects and enums are found
+	for i := 0; i < len(g.types); i++ {
+		if err := g.generateType(g.types[i]); err != nil {
+			return nil, fmt.Errorf("type %s: %w", g.types[i].name, err)
+		}
+	}
+
+	source, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return source, nil
+}
+
+// declare adds named type and returns its unique name
+func (g *generator) declare(name string, description *string, schema entity.DataType) string {
+	name = g.uniqueName(name)
+	g.types = append(g.types, &goType{name: name, description: description, schema: schema})
+	return name
+}
+
+func (g *generator) uniqueName(name string) string {
+	return uniqueIn(g.names, name)
+}
+
+// uniqueIn returns name with number suffix, that is not in names, and adds it to names
+func uniqueIn(names map[string]bool, name string) string {
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	names[unique] = true
+	return unique
+}
+
+// nilable reports whether zero value of Go type is nil
+func nilable(goType string) bool {
+	return goType == "any" || strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") ||
+		strings.HasPrefix(goType, "map[")
+}
+
+func (g *generator) generateType(typ *goType) error {
+	g.buf.WriteString("\n")
+	description := typ.description
+	if description == nil {
+		description = schemaDescription(typ.schema)
+	}
+	writeComment(&g.buf, "", description)
+
+	switch schema := typ.schema.(type) {
+	case *entity.StringSchema:
+		fmt.Fprintf(&g.buf, "type %s string\n", typ.name)
+		g.generateEnum(typ.name, enumValues(schema.Enum), true)
+		return nil
+	case *entity.IntegerSchema:
+		fmt.Fprintf(&g.buf, "type %s int\n", typ.name)
+		g.generateEnum(typ.name, enumValues(schema.Enum), false)
+		return nil
+	}
+
+	properties, required, ok := objectProperties(typ.schema)
+	if !ok {
+		goType, err := g.fieldType(typ.name, typ.schema)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.buf, "type %s %s\n", typ.name, goType)
+		return nil
+	}
+
+	fmt.Fprintf(&g.buf, "type %s struct {\n", typ.name)
+	fieldNames := make(map[string]bool)
+	for _, name := range properties.Keys() {
+		property, _ := properties.Get(name)
+		fieldName := uniqueIn(fieldNames, fieldGoName(name))
+		if err := g.generateField(typ.name, fieldName, name, property, contains(required, name)); err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+	}
+	g.buf.WriteString("}\n")
+	return nil
+}
+
+func (g *generator) generateField(owner, fieldName, property string, schema entity.DataType, required bool) error {
+	fieldType, err := g.fieldType(owner+fieldName, schema)
+	if err != nil {
+		return err
+	}
+	jsonTag := property
+	if !required {
+		// Empty slices and maps are omitted, so they need no pointer
+		if !nilable(fieldType) {
+			fieldType = "*" + fieldType
+		}
+		jsonTag += ",omitempty"
+	}
+
+	tags := []string{fmt.Sprintf("json:%q", jsonTag)}
+	keywords, skipped := g.keywordTags(schema)
+	if len(keywords) > 0 {
+		tags = append(tags, fmt.Sprintf("jsonschema:%q", strings.Join(keywords, ",")))
+	}
+
+	writeComment(&g.buf, "\t", schemaDescription(schema))
+	for _, keyword := range skipped {
+		fmt.Fprintf(&g.buf, "\t// jsonschema: %s can not be expressed in struct tag\n", keyword)
+	}
+	fmt.Fprintf(&g.buf, "\t%s %s `%s`\n", fieldName, fieldType, strings.Join(tags, " "))
+	return nil
+}
+
+// fieldType returns Go type of schema. Inline objects and enums are declared with name.
+func (g *generator) fieldType(name string, schema entity.DataType) (string, error) {
+	switch schema := schema.(type) {
+	case *entity.StringSchema:
+		if len(schema.Enum) > 0 {
+			return g.declare(name, nil, schema), nil
+		}
+		return "string", nil
+	case *entity.IntegerSchema:
+		if len(schema.Enum) > 0 {
+			return g.declare(name, nil, schema), nil
+		}
+		return "int", nil
+	case *entity.NumberSchema:
+		return "float64", nil
+	case *entity.BooleanSchema:
+		return "bool", nil
+	case *entity.NullSchema:
+		return "any", nil
+	case *entity.ArraySchema:
+		if schema.Items == nil {
+			return "[]any", nil
+		}
+		itemType, err := g.fieldType(name+"Item", schema.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemType, nil
+	case *entity.ObjectSchema:
+		return g.objectType(name, schema, schema)
+	case *entity.JSONSchema:
+		if schema.Ref != nil {
+			return g.refType(*schema.Ref)
+		}
+		if schema.Properties.Len() > 0 {
+			return g.declare(name, nil, schema), nil
+		}
+		// Single type can be nullable, several types can not be expressed by Go type
+		var types []entity.JSONSchemaDataType
+		for _, typ := range schema.Type {
+			if typ != entity.JSONSchemaNull {
+				types = append(types, typ)
+			}
+		}
+		if len(types) == 1 {
+			if scalar, ok := scalarTypes[types[0]]; ok {
+				if len(types) < len(schema.Type) {
+					return "*" + scalar, nil
+				}
+				return scalar, nil
+			}
+		}
+		if schema.IsTypes([]entity.JSONSchemaDataType{entity.JSONSchemaObject}) {
+			return g.objectType(name, schema, &schema.ObjectSchema)
+		}
+		return "any", nil
+	}
+	return "", fmt.Errorf("unsupported schema %T", schema)
+}
+
+var scalarTypes = map[entity.JSONSchemaDataType]string{
+	entity.JSONSchemaString:  "string",
+	entity.JSONSchemaInteger: "int",
+	entity.JSONSchemaNumber:  "float64",
+	entity.JSONSchemaBoolean: "bool",
+}
+
+// objectType declares struct for object with properties and returns map for other objects
+func (g *generator) objectType(name string, schema entity.DataType, object *entity.ObjectSchema) (string, error) {
+	if object.Properties.Len() > 0 {
+		return g.declare(name, nil, schema), nil
+	}
+	if object.AdditionalProperties != nil && object.AdditionalProperties.Schema != nil {
+		valueType, err := g.fieldType(name+"Value", object.AdditionalProperties.Schema)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + valueType, nil
+	}
+	return "map[string]any", nil
+}
+
+func (g *generator) refType(ref string) (string, error) {
+	if typeName, ok := g.defNames[defName(ref)]; ok {
+		return typeName, nil
+	}
+	return "", fmt.Errorf("unsupported reference %s", ref)
+}
+
+// defName returns name of definition from local reference
+func defName(ref string) string {
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// keywordTags returns constraints of field schema as jsonschema tags. Constraints of referenced scalar
+// types are included, because the converter inlines such types. Values, that break struct tag, are skipped.
+func (g *generator) keywordTags(schema entity.DataType) (tags []string, skipped []string) {
+	if ref, ok := schema.(*entity.JSONSchema); ok && ref.Ref != nil {
+		if def, ok := g.defs.Get(defName(*ref.Ref)); ok {
+			schema = def
+		}
+	}
+
+	var keywords [][2]string
+	add := func(keyword string, value any) {
+		keywords = append(keywords, [2]string{keyword, fmt.Sprint(value)})
+	}
+	switch schema := schema.(type) {
+	case *entity.StringSchema:
+		addIf(add, "minLength", schema.MinLength)
+		addIf(add, "maxLength", schema.MaxLength)
+		addIf(add, "pattern", schema.Pattern)
+		addIf(add, "format", schema.Format)
+		addIf(add, "default", schema.Default)
+		for _, value := range schema.Enum {
+			addIf(add, "enum", value)
+		}
+	case *entity.IntegerSchema:
+		numericKeywords(add, &schema.NumericSchema)
+	case *entity.NumberSchema:
+		numericKeywords(add, &schema.NumericSchema)
+	}
+
+	for _, keyword := range keywords {
+		if strings.ContainsAny(keyword[1], ",\" `\\") {
+			skipped = append(skipped, keyword[0]+"="+keyword[1])
+			continue
+		}
+		tags = append(tags, keyword[0]+"="+keyword[1])
+	}
+	return tags, skipped
+}
+
+func numericKeywords[T int | float64](add func(string, any), schema *entity.NumericSchema[T]) {
+	addIf(add, "multipleOf", schema.MultipleOf)
+	addIf(add, "minimum", schema.Minimum)
+	addIf(add, "maximum", schema.Maximum)
+	addIf(add, "exclusiveMinimum", schema.ExclusiveMinimum)
+	addIf(add, "exclusiveMaximum", schema.ExclusiveMaximum)
+	addIf(add, "default", schema.Default)
+	for _, value := range schema.Enum {
+		addIf(add, "enum", value)
+	}
+}
+
+func addIf[T any](add func(string, any), keyword string, value *T) {
+	if value != nil {
+		add(keyword, *value)
+	}
+}
+
+// generateEnum declares typed constants of enum values
+func (g *generator) generateEnum(typeName string, values []string, quote bool) {
+	if len(values) == 0 {
+		return
+	}
+	g.buf.WriteString("\nconst (\n")
+	for _, value := range values {
+		suffix := goName(value)
+		if strings.HasPrefix(value, "-") {
+			suffix = "Minus" + suffix
+		}
+		if suffix == "" {
+			suffix = "Empty"
+		}
+		name := g.uniqueName(typeName + suffix)
+		literal := value
+		if quote {
+			literal = strconv.Quote(value)
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s = %s\n", name, typeName, literal)
+	}
+	g.buf.WriteString(")\n")
+}
+
+func enumValues[T any](enum []*T) []string {
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		if value != nil {
+			values = append(values, fmt.Sprint(*value))
+		}
+	}
+	return values
+}
+
+// objectProperties returns properties of object schema
+func objectProperties(schema entity.DataType) (*entity.OrderedMap[entity.DataType], []string, bool) {
+	var object *entity.ObjectSchema
+	switch schema := schema.(type) {
+	case *entity.ObjectSchema:
+		object = schema
+	case *entity.JSONSchema:
+		object = &schema.ObjectSchema
+	default:
+		return nil, nil, false
+	}
+	// Objects with schema of additional properties are maps
+	isMap := object.AdditionalProperties != nil && object.AdditionalProperties.Schema != nil
+	isStruct := object.Properties.Len() > 0 || (object.IsType(entity.JSONSchemaObject) && !isMap)
+	return object.Properties, object.Required, isStruct
+}
+
+func schemaDescription(schema entity.DataType) *string {
+	switch schema := schema.(type) {
+	case *entity.JSONSchema:
+		return schema.Description
+	case *entity.ObjectSchema:
+		return schema.Description
+	case *entity.StringSchema:
+		return schema.Description
+	case *entity.IntegerSchema:
+		return schema.Description
+	case *entity.NumberSchema:
+		return schema.Description
+	case *entity.BooleanSchema:
+		return schema.Description
+	case *entity.ArraySchema:
+		return schema.Description
+	}
+	return nil
+}
+
+func writeComment(buf *bytes.Buffer, indent string, description *string) {
+	if description == nil || *description == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(*description), "\n") {
+		fmt.Fprintf(buf, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+func rootName(schema *entity.JSONSchema, config Config) string {
+	if config.RootName != "" {
+		return config.RootName
+	}
+	if schema.Title != nil && exportedName(*schema.Title) != "" {
+		return exportedName(*schema.Title)
+	}
+	if schema.ID != nil {
+		id := strings.TrimSuffix(strings.TrimRight(*schema.ID, "#/"), ".json")
+		if name := exportedName(id[strings.LastIndex(id, "/")+1:]); name != "" {
+			return name
+		}
+	}
+	return "Root"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go names
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true,
+	"SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "URI": true,
+	"URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// goName converts JSON name to exported Go name, e.g. user_id and userId to UserID
+func goName(name string) string {
+	var builder strings.Builder
+	for _, word := range splitWords(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			builder.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		builder.WriteRune(unicode.ToUpper(runes[0]))
+		builder.WriteString(string(runes[1:]))
+	}
+	return builder.String()
+}
+
+// exportedName converts JSON name to exported Go identifier. Names starting with digit get N prefix.
+func exportedName(name string) string {
+	result := goName(name)
+	if result != "" && !unicode.IsLetter([]rune(result)[0]) {
+		result = "N" + result
+	}
+	return result
+}
+
+// fieldGoName converts property name to exported field name. Names, that do not start with letter, get
+// Field prefix, e.g. 2fa to Field2fa and _ to Field.
+func fieldGoName(name string) string {
+	result := goName(name)
+	if result == "" || !unicode.IsLetter([]rune(result)[0]) {
+		result = "Field" + result
+	}
+	return result
+}
+
+// splitWords splits name by separators and by lower to upper case changes
+func splitWords(name string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	for i, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && len(word) > 0 && unicode.IsLower(word[len(word)-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	return words
+}
@@ -0,0 +1,136 @@
+// Package compatibility checks compatibility of schema versions like schema registry does.
+package compatibility
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/diff"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Level is compatibility level of schema versions
+type Level string
+
+const (
+	// None allows any change
+	None Level = "NONE"
+	// Backward requires, that new schema accepts data of the latest version, so consumers can be upgraded first
+	Backward Level = "BACKWARD"
+	// BackwardTransitive requires, that new schema accepts data of all versions
+	BackwardTransitive Level = "BACKWARD_TRANSITIVE"
+	// Forward requires, that the latest version accepts data of new schema, so producers can be upgraded first
+	Forward Level = "FORWARD"
+	// ForwardTransitive requires, that all versions accept data of new schema
+	ForwardTransitive Level = "FORWARD_TRANSITIVE"
+	// Full requires backward and forward compatibility with the latest version
+	Full Level = "FULL"
+	// FullTransitive requires backward and forward compatibility with all versions
+	FullTransitive Level = "FULL_TRANSITIVE"
+)
+
+var ErrUnknownLevel = errors.New("unknown compatibility level")
+
+var levels = []Level{None, Backward, BackwardTransitive, Forward, ForwardTransitive, Full, FullTransitive}
+
+// ParseLevel returns level by name, e.g. backward_transitive. Name is case-insensitive.
+func ParseLevel(name string) (Level, error) {
+	for _, level := range levels {
+		if strings.EqualFold(name, string(level)) {
+			return level, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownLevel, name)
+}
+
+// Transitive reports whether new schema is checked against all versions instead of the latest one
+func (l Level) Transitive() bool {
+	return strings.HasSuffix(string(l), "_TRANSITIVE")
+}
+
+// breakingChanges returns changes of report, that are not allowed by level
+func (l Level) breakingChanges(report *diff.Report) []diff.Change {
+	backward := l == Backward || l == BackwardTransitive || l == Full || l == FullTransitive
+	forward := l == Forward || l == ForwardTransitive || l == Full || l == FullTransitive
+	var changes []diff.Change
+	for _, change := range report.Changes {
+		// Data of old version is rejected by new schema or data of new schema is rejected by old version
+		if (backward && change.BreaksProducers) || (forward && change.BreaksConsumers) {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// IncompatibleError describes changes of new schema, that are not allowed by level
+type IncompatibleError struct {
+	Level Level
+	// Version is number of incompatible version in history starting from 1
+	Version int
+	Changes []diff.Change
+}
+
+func (e *IncompatibleError) Error() string {
+	messages := make([]string, 0, len(e.Changes))
+	for _, change := range e.Changes {
+		messages = append(messages, fmt.Sprintf("#%s: %s", change.Location, change.Message))
+	}
+	return fmt.Sprintf("schema is not %s compatible with version %d: %s", e.Level, e.Version,
+		strings.Join(messages, "; "))
+}
+
+// Check checks compatibility of schema with history of versions ordered from the oldest to the latest.
+// Every incompatible version is reported by IncompatibleError, versions are checked from the latest.
+func Check(level Level, history [][]byte, schema []byte) error {
+	if !slices.Contains(levels, level) {
+		return fmt.Errorf("%w: %s", ErrUnknownLevel, level)
+	}
+	if level == None || len(history) == 0 {
+		return nil
+	}
+
+	first := len(history) - 1
+	if level.Transitive() {
+		first = 0
+	}
+	var errs []error
+	for i := len(history) - 1; i >= first; i-- {
+		report, err := diff.CompareJSON(history[i], schema)
+		if err != nil {
+			return fmt.Errorf("compare with version %d: %w", i+1, err)
+		}
+		if changes := level.breakingChanges(report); len(changes) > 0 {
+			errs = append(errs, &IncompatibleError{Level: level, Version: i + 1, Changes: changes})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckHistory checks, that every version is compatible with previous versions.
+// Versions are ordered from the oldest to the latest.
+func CheckHistory(level Level, versions [][]byte) error {
+	for i := 1; i < len(versions); i++ {
+		if err := Check(level, versions[:i], versions[i]); err != nil {
+			return fmt.Errorf("version %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// CheckFiles checks compatibility of schema file with history files ordered from the oldest to the latest
+func CheckFiles(level Level, history []string, filename string) error {
+	versions := make([][]byte, 0, len(history))
+	for _, historyFile := range history {
+		data, err := os.ReadFile(historyFile)
+		if err != nil {
+			return err
+		}
+		versions = append(versions, data)
+	}
+	schema, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return Check(level, versions, schema)
+}
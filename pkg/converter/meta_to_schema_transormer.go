@@ -2,8 +2,12 @@ package converter
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"net/url"
+	"path"
+	"slices"
 	"strings"
 )
 
@@ -11,6 +15,18 @@ type Converter interface {
 	Convert(config entity.Config, metadata *entity.JsonSchemaMetadata) (*entity.JSONSchema, error)
 }
 
+// MultiConverter converts metadata with several root types
+type MultiConverter interface {
+	// ConvertEach creates standalone schema for every root type. Schemas are keyed by root type ID.
+	ConvertEach(config entity.Config, metadata *entity.JsonSchemaMetadata) (map[string]*entity.JSONSchema, error)
+	// ConvertBundle creates single schema document with every type under $defs
+	ConvertBundle(config entity.Config, metadata *entity.JsonSchemaMetadata) (*entity.JSONSchema, error)
+}
+
+var ErrRootNotFound = errors.New("metadata has no single root type")
+var ErrInvalidSchemaID = errors.New("schema $id is not absolute URI")
+var ErrDefinitionConflict = errors.New("types of different packages have the same definition name")
+
 type MetaToSchemaConverter struct{}
 
 func NewMetaToSchemaConverter() *MetaToSchemaConverter {
@@ -18,73 +34,224 @@ func NewMetaToSchemaConverter() *MetaToSchemaConverter {
 }
 
 func (c *MetaToSchemaConverter) Convert(config entity.Config, metadata *entity.JsonSchemaMetadata) (*entity.JSONSchema, error) {
-	schema := entity.NewJSONSchema().
-		SetSchema(config.SchemaVersion).
-		SetID(c.getIdFromRootType(metadata.Root))
+	if metadata.Root == nil {
+		return nil, ErrRootNotFound
+	}
+	schema := entity.NewJSONSchema().SetSchema(config.SchemaVersion)
+	if !config.OmitSchemaID {
+		id := c.getIdFromRootType(config, metadata.Root)
+		if err := validateSchemaID(id); err != nil {
+			return nil, err
+		}
+		schema.SetID(id)
+	}
 
-	definitions, err := createDefinitions(metadata.Types)
+	types := metadata.Types
+	if config.ExternalRefs {
+		types = packageTypes(types, metadata.Root.Package)
+	}
+	definitions, err := createDefinitions(config, types, c.definitionRefs(config, metadata.Root.Package))
 	if err != nil {
 		return nil, err
 	}
-	schema.Defs = definitions
-	rootObject := schema.Defs[metadata.Root.TypeName].(*entity.ObjectSchema)
-	delete(schema.Defs, metadata.Root.TypeName)
+	rootDefinition, _ := definitions.Get(metadata.Root.TypeName)
+	rootObject := rootDefinition.(*entity.ObjectSchema)
+	definitions.Delete(metadata.Root.TypeName)
+	if err = c.identifyDefinitions(config, definitions, types); err != nil {
+		return nil, err
+	}
+	if definitions.Len() > 0 {
+		schema.Defs = definitions
+	}
 	schema.Properties = rootObject.Properties
 	schema.Required = rootObject.Required
 	return schema, nil
 }
 
-func createDefinitions(dataTypeDefinitions map[string]*entity.DataTypeMetadata) (map[string]entity.DataType, error) {
+func (c *MetaToSchemaConverter) ConvertEach(config entity.Config, metadata *entity.JsonSchemaMetadata) (map[string]*entity.JSONSchema, error) {
+	schemas := make(map[string]*entity.JSONSchema)
+	for _, root := range metadata.RootTypes() {
+		// Every schema contains only definitions of types used by its root
+		rootMetadata := &entity.JsonSchemaMetadata{Types: reachableTypes(root), Root: root}
+		schema, err := c.Convert(config, rootMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("convert %s: %w", root.TypeName, err)
+		}
+		schemas[root.ID()] = schema
+	}
+	return schemas, nil
+}
+
+func (c *MetaToSchemaConverter) ConvertBundle(config entity.Config, metadata *entity.JsonSchemaMetadata) (*entity.JSONSchema, error) {
+	schema := entity.NewJSONEmptySchema().SetSchema(config.SchemaVersion)
+	types := metadata.Types
+	// Bundle of several packages contains all types, none of them is external
+	localPackage := ""
+	if id, ok := c.getIdFromRootTypes(config, metadata.RootTypes()); ok {
+		if !config.OmitSchemaID {
+			if err := validateSchemaID(id); err != nil {
+				return nil, err
+			}
+			schema.SetID(id)
+		}
+		localPackage = metadata.RootTypes()[0].Package
+		if config.ExternalRefs {
+			types = packageTypes(types, localPackage)
+		}
+	}
+
+	definitions, err := createDefinitions(config, types, c.definitionRefs(config, localPackage))
+	if err != nil {
+		return nil, err
+	}
+	if err = c.identifyDefinitions(config, definitions, types); err != nil {
+		return nil, err
+	}
+	schema.Defs = definitions
+	return schema, nil
+}
+
+// reachableTypes returns struct types used by root including root itself
+func reachableTypes(root *entity.DataTypeMetadata) map[string]*entity.DataTypeMetadata {
+	types := make(map[string]*entity.DataTypeMetadata)
+	var walk func(dataTypeMetadata *entity.DataTypeMetadata)
+	walk = func(dataTypeMetadata *entity.DataTypeMetadata) {
+		if dataTypeMetadata.Ref != nil {
+			dataTypeMetadata = dataTypeMetadata.Ref
+		}
+		if _, ok := types[dataTypeMetadata.ID()]; ok {
+			return
+		}
+		types[dataTypeMetadata.ID()] = dataTypeMetadata
+		walkNodes(dataTypeMetadata.Nodes, walk)
+	}
+	walk(root)
+	return types
+}
+
+// walkNodes calls walk for every struct used by nodes including elements of collections
+func walkNodes(nodes []*entity.DataTypeMetadata, walk func(dataTypeMetadata *entity.DataTypeMetadata)) {
+	for _, node := range nodes {
+		if node.Ref != nil {
+			walk(node.Ref)
+		} else {
+			walkNodes(node.Nodes, walk)
+		}
+	}
+}
+
+// packageTypes returns types declared in package
+func packageTypes(types map[string]*entity.DataTypeMetadata, packageName string) map[string]*entity.DataTypeMetadata {
+	result := make(map[string]*entity.DataTypeMetadata, len(types))
+	for id, dataTypeMetadata := range types {
+		if dataTypeMetadata.Package == packageName {
+			result[id] = dataTypeMetadata
+		}
+	}
+	return result
+}
+
+// definitionRefFunc returns reference to schema of struct
+type definitionRefFunc func(dataTypeMetadata *entity.DataTypeMetadata) string
+
+// definitionRefs returns references into $defs. With Config.ExternalRefs structs declared out of localPackage
+// are referenced by $id of their own schema. Definitions with $id are referenced by the $id too, because
+// fragment #/$defs/<type> resolves against $id of the definition containing the reference.
+func (c *MetaToSchemaConverter) definitionRefs(config entity.Config, localPackage string) definitionRefFunc {
+	return func(dataTypeMetadata *entity.DataTypeMetadata) string {
+		external := config.ExternalRefs && localPackage != "" && dataTypeMetadata.Package != localPackage
+		if external || config.DefinitionIDs == entity.DefinitionSchemaID {
+			return c.getIdFromRootType(config, dataTypeMetadata)
+		}
+		return fmt.Sprintf("#/$defs/%s", dataTypeMetadata.TypeName)
+	}
+}
+
+// createDefinitions creates schema of every struct. Definitions are sorted by name, properties are in order of
+// struct fields or sorted by name with Config.SortProperties.
+func createDefinitions(config entity.Config,
+	dataTypeDefinitions map[string]*entity.DataTypeMetadata, refs definitionRefFunc) (*entity.OrderedMap[entity.DataType], error) {
 	if len(dataTypeDefinitions) == 0 {
 		return nil, nil
 	}
-	definitions := make(map[string]entity.DataType)
+	// Definitions are named by type name, so types of different packages must not share it
+	ids := make(map[string]string, len(dataTypeDefinitions))
+	for id, dataTypeMetadata := range dataTypeDefinitions {
+		if other, ok := ids[dataTypeMetadata.TypeName]; ok {
+			conflict := []string{other, id}
+			slices.Sort(conflict)
+			return nil, fmt.Errorf("%w: %s and %s", ErrDefinitionConflict, conflict[0], conflict[1])
+		}
+		ids[dataTypeMetadata.TypeName] = id
+	}
+
+	definitions := entity.NewOrderedMap[entity.DataType]()
 	for _, dataTypeMetadata := range dataTypeDefinitions {
 		dataType := typeKindToJsonSchemaType(dataTypeMetadata.TypeKind)
 		if dataType != entity.JSONSchemaObject {
 			return nil, fmt.Errorf("invalid data type for definisions: %s. Only struct supported", dataTypeMetadata.TypeKind)
 		}
-		_, err := transformObjectToObjectSchema(definitions, dataTypeMetadata)
+		objectSchema, err := transformObjectToObjectSchema(definitions, dataTypeMetadata, refs)
 		if err != nil {
 			return nil, err
 		}
+		if config.SortProperties {
+			objectSchema.Properties.SortKeys()
+		}
 	}
+	definitions.SortKeys()
 	return definitions, nil
 }
 
-func transformObjectToObjectSchema(definitions map[string]entity.DataType,
-	dataTypeMetadata *entity.DataTypeMetadata) (*entity.ObjectSchema, error) {
+func transformObjectToObjectSchema(definitions *entity.OrderedMap[entity.DataType],
+	dataTypeMetadata *entity.DataTypeMetadata, refs definitionRefFunc) (*entity.ObjectSchema, error) {
 	if dataTypeMetadata.Ref != nil {
 		dataTypeMetadata = dataTypeMetadata.Ref
 	}
-	if objectSchema, ok := definitions[dataTypeMetadata.ID()]; ok {
+	if objectSchema, ok := definitions.Get(dataTypeMetadata.TypeName); ok {
 		return objectSchema.(*entity.ObjectSchema), nil
 	}
 
 	objectSchema := entity.NewObjectSchema()
-	definitions[dataTypeMetadata.TypeName] = objectSchema
+	definitions.Set(dataTypeMetadata.TypeName, objectSchema)
 	for _, node := range dataTypeMetadata.Nodes {
 		dataType := typeKindToJsonSchemaType(node.TypeKind)
-		if !node.IsPointer {
-			objectSchema.Required = append(objectSchema.Required, getFieldName(node))
+		if !isOptional(node) {
+			objectSchema.Required = append(objectSchema.Required, FieldName(node))
 		}
 		switch dataType {
 		case entity.JSONSchemaNumber:
-			objectSchema.AddProperty(getFieldName(node), entity.NewNumberSchema())
+			numberSchema, err := transformNumberToNumberSchema(node)
+			if err != nil {
+				return nil, err
+			}
+			objectSchema.AddProperty(FieldName(node), numberSchema)
 		case entity.JSONSchemaString:
-			objectSchema.AddProperty(getFieldName(node), entity.NewStringSchema())
+			stringSchema, err := transformStringToStringSchema(node)
+			if err != nil {
+				return nil, err
+			}
+			objectSchema.AddProperty(FieldName(node), stringSchema)
 		case entity.JSONSchemaBoolean:
-			objectSchema.AddProperty(getFieldName(node), entity.NewBooleanSchema())
+			objectSchema.AddProperty(FieldName(node), entity.NewBooleanSchema())
 		case entity.JSONSchemaInteger:
 			integerSchema, err := transformIntegerToIntegerSchema(node)
 			if err != nil {
 				return nil, err
 			}
-			objectSchema.AddProperty(getFieldName(node), integerSchema)
+			objectSchema.AddProperty(FieldName(node), integerSchema)
+		case entity.JSONSchemaArray, entity.JSONSchemaObject:
+			collectionSchema, err := transformCollectionToSchema(node, refs)
+			if err != nil {
+				return nil, err
+			}
+			objectSchema.AddProperty(FieldName(node), collectionSchema)
 		case entity.JSONSchemaUnknown:
 			if node.Ref != nil {
-				schema := entity.NewJSONEmptySchema().SetRef(fmt.Sprintf("#/$defs/%s", node.Ref.TypeName))
-				objectSchema.AddProperty(getFieldName(node), schema)
+				schema := entity.NewJSONEmptySchema().SetRef(refs(node.Ref))
+				objectSchema.AddProperty(FieldName(node), schema)
+			} else if isAnyType(node) {
+				objectSchema.AddProperty(FieldName(node), entity.NewJSONEmptySchema())
 			} else {
 				return nil, fmt.Errorf("invalid object field %s for %s (%s)", dataTypeMetadata.TypeName,
 					node.TypeName, node.TypeKind)
@@ -97,48 +264,151 @@ func transformObjectToObjectSchema(definitions map[string]entity.DataType,
 	return objectSchema, nil
 }
 
+// transformCollectionToSchema creates schema of slice, array or map field. Map is object with schema
+// of values in additionalProperties.
+func transformCollectionToSchema(dataTypeMetadata *entity.DataTypeMetadata, refs definitionRefFunc) (entity.DataType, error) {
+	if isAnyType(dataTypeMetadata) {
+		return entity.NewJSONEmptySchema(), nil
+	}
+	if len(dataTypeMetadata.Nodes) != 1 {
+		return nil, fmt.Errorf("field %s: unknown element type of %s", dataTypeMetadata.FieldName,
+			dataTypeMetadata.TypeKind)
+	}
+	elemMetadata := dataTypeMetadata.Nodes[0]
+	// encoding/json encodes byte slice as base64 string
+	if dataTypeMetadata.TypeKind == "slice" && elemMetadata.TypeKind == "uint8" {
+		stringSchema := entity.NewStringSchema()
+		contentEncoding := "base64"
+		stringSchema.ContentEncoding = &contentEncoding
+		return stringSchema, nil
+	}
+
+	elemSchema, err := transformElementToSchema(elemMetadata, refs)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", dataTypeMetadata.FieldName, err)
+	}
+	if dataTypeMetadata.TypeKind == "map" {
+		return entity.NewObjectSchema().SetAdditionalProperties(elemSchema), nil
+	}
+	return entity.NewArraySchema(elemSchema), nil
+}
+
+// transformElementToSchema creates schema of collection element. Nested collections are not supported.
+func transformElementToSchema(elemMetadata *entity.DataTypeMetadata, refs definitionRefFunc) (*entity.JSONSchema, error) {
+	if elemMetadata.Ref != nil {
+		return entity.NewJSONEmptySchema().SetRef(refs(elemMetadata.Ref)), nil
+	}
+	if isAnyType(elemMetadata) {
+		return entity.NewJSONEmptySchema(), nil
+	}
+	switch dataType := typeKindToJsonSchemaType(elemMetadata.TypeKind); dataType {
+	case entity.JSONSchemaString, entity.JSONSchemaNumber, entity.JSONSchemaInteger, entity.JSONSchemaBoolean:
+		return entity.NewJSONEmptySchema().SetType(dataType), nil
+	}
+	return nil, fmt.Errorf("not supported element type %s (%s)", elemMetadata.TypeName, elemMetadata.TypeKind)
+}
+
+// rawJSONTypes are IDs of types with raw JSON. json.RawMessage is alias of jsontext.Value with jsonv2 experiment.
+var rawJSONTypes = []string{"encoding/json#RawMessage", "encoding/json/jsontext#Value"}
+
+// isAnyType reports whether value of type can be any JSON: interface or json.RawMessage
+func isAnyType(dataTypeMetadata *entity.DataTypeMetadata) bool {
+	return dataTypeMetadata.TypeKind == "interface" || slices.Contains(rawJSONTypes, dataTypeMetadata.ID())
+}
+
 func transformIntegerToIntegerSchema(dataTypeMetadata *entity.DataTypeMetadata) (*entity.IntegerSchema, error) {
 	integerSchema := entity.NewIntegerSchema()
 	if jsonschemaTags, ok := dataTypeMetadata.Tags["jsonschema"]; ok {
-		err := numericalKeywords(integerSchema, jsonschemaTags)
+		err := numericalKeywords(&integerSchema.NumericSchema, jsonschemaTags)
 		if err != nil {
-			return nil, fmt.Errorf("Field %s: ", dataTypeMetadata.FieldName)
+			return nil, fmt.Errorf("field %s: %w", dataTypeMetadata.FieldName, err)
 		}
 	}
 	return integerSchema, nil
 }
 
+func transformNumberToNumberSchema(dataTypeMetadata *entity.DataTypeMetadata) (*entity.NumberSchema, error) {
+	numberSchema := entity.NewNumberSchema()
+	if jsonschemaTags, ok := dataTypeMetadata.Tags["jsonschema"]; ok {
+		err := numericalKeywords(&numberSchema.NumericSchema, jsonschemaTags)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", dataTypeMetadata.FieldName, err)
+		}
+	}
+	return numberSchema, nil
+}
+
+func transformStringToStringSchema(dataTypeMetadata *entity.DataTypeMetadata) (*entity.StringSchema, error) {
+	stringSchema := entity.NewStringSchema()
+	if jsonschemaTags, ok := dataTypeMetadata.Tags["jsonschema"]; ok {
+		err := stringKeywords(stringSchema, jsonschemaTags)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", dataTypeMetadata.FieldName, err)
+		}
+	}
+	return stringSchema, nil
+}
+
+// read struct tags for string type keywords
+func stringKeywords(schema *entity.StringSchema, tags []string) error {
+	for _, tag := range tags {
+		nameValue := strings.SplitN(tag, "=", 2)
+		if len(nameValue) == 2 {
+			name, val := nameValue[0], nameValue[1]
+			switch name {
+			case "minLength":
+				schema.MinLength, _ = toJSONNumber[int](val)
+			case "maxLength":
+				schema.MaxLength, _ = toJSONNumber[int](val)
+			case "pattern":
+				schema.Pattern = &val
+			case "format":
+				schema.Format = &val
+			case "default":
+				schema.Default = &val
+			case "example":
+				schema.Examples = append(schema.Examples, &val)
+			case "enum":
+				schema.Enum = append(schema.Enum, &val)
+			default:
+				return fmt.Errorf("invalid tag for string schema %s", tag)
+			}
+		}
+	}
+	return nil
+}
+
 // read struct tags for numerical type keywords
-func numericalKeywords(schema *entity.IntegerSchema, tags []string) error {
+func numericalKeywords[T int | float64](schema *entity.NumericSchema[T], tags []string) error {
 	for _, tag := range tags {
 		nameValue := strings.Split(tag, "=")
 		if len(nameValue) == 2 {
 			name, val := nameValue[0], nameValue[1]
 			switch name {
 			case "multipleOf":
-				schema.MultipleOf, _ = toJSONNumber[int](val)
+				schema.MultipleOf, _ = toJSONNumber[T](val)
 			case "minimum":
-				schema.Minimum, _ = toJSONNumber[int](val)
+				schema.Minimum, _ = toJSONNumber[T](val)
 			case "maximum":
-				schema.Maximum, _ = toJSONNumber[int](val)
+				schema.Maximum, _ = toJSONNumber[T](val)
 			case "exclusiveMaximum":
-				schema.ExclusiveMaximum, _ = toJSONNumber[int](val)
+				schema.ExclusiveMaximum, _ = toJSONNumber[T](val)
 			case "exclusiveMinimum":
-				schema.ExclusiveMinimum, _ = toJSONNumber[int](val)
+				schema.ExclusiveMinimum, _ = toJSONNumber[T](val)
 			case "default":
-				if num, ok := toJSONNumber[int](val); ok {
+				if num, ok := toJSONNumber[T](val); ok {
 					schema.Default = num
 				}
 			case "example":
-				if num, ok := toJSONNumber[int](val); ok {
+				if num, ok := toJSONNumber[T](val); ok {
 					schema.Examples = append(schema.Examples, num)
 				}
 			case "enum":
-				if num, ok := toJSONNumber[int](val); ok {
+				if num, ok := toJSONNumber[T](val); ok {
 					schema.Enum = append(schema.Enum, num)
 				}
 			default:
-				return fmt.Errorf("invalid tag for integer schema %s", tag)
+				return fmt.Errorf("invalid tag for numeric schema %s", tag)
 			}
 		}
 	}
@@ -163,7 +433,21 @@ func toJSONNumber[T int | float64](s string) (*T, bool) {
 	return result, false
 }
 
-func getFieldName(metadata *entity.DataTypeMetadata) string {
+// isOptional reports whether field can be absent in JSON: pointer or field with omitempty, that encoding/json
+// omits for empty value. Struct values are never omitted, so struct field with omitempty stays required
+func isOptional(metadata *entity.DataTypeMetadata) bool {
+	if metadata.IsPointer {
+		return true
+	}
+	if metadata.Ref != nil {
+		return false
+	}
+	jsonTags := metadata.Tags["json"]
+	return len(jsonTags) > 1 && slices.Contains(jsonTags[1:], "omitempty")
+}
+
+// FieldName returns name of field metadata in schema properties: name from json tag or Go field name
+func FieldName(metadata *entity.DataTypeMetadata) string {
 	if jsonTags, ok := metadata.Tags["json"]; ok {
 		return jsonTags[0]
 	}
@@ -190,20 +474,78 @@ func typeKindToJsonSchemaType(typeKind string) entity.JSONSchemaDataType {
 	return entity.JSONSchemaUnknown
 }
 
-func (c *MetaToSchemaConverter) getIdFromRootType(rootMetadata *entity.DataTypeMetadata) string {
-	// Attempt to set the schema ID
-	return fmt.Sprintf("https://%s/%s", rootMetadata.Package, rootMetadata.TypeName)
-	//if !r.Anonymous && s.ID == EmptyID {
-	//	baseSchemaID := r.BaseSchemaID
-	//	if baseSchemaID == EmptyID {
-	//		id := ID("https://" + t.PkgPath())
-	//		if err := id.Validate(); err == nil {
-	//			// it's okay to silently ignore URL errors
-	//			baseSchemaID = id
-	//		}
-	//	}
-	//	if baseSchemaID != EmptyID {
-	//		s.ID = baseSchemaID.Add(ToSnakeCase(name))
-	//	}
-	//}
+// getIdFromRootTypes returns id of the package, if all root types are declared in one package.
+// Configured base URI is id of any root types.
+func (c *MetaToSchemaConverter) getIdFromRootTypes(config entity.Config, roots []*entity.DataTypeMetadata) (string, bool) {
+	if len(roots) == 0 {
+		return "", false
+	}
+	for _, root := range roots[1:] {
+		if root.Package != roots[0].Package && config.SchemaIDBase == "" {
+			return "", false
+		}
+	}
+	return schemaIDBase(config, roots[0].Package), true
+}
+
+// getIdFromRootType returns $id of schema of type, see entity.Config.SchemaIDTemplate
+func (c *MetaToSchemaConverter) getIdFromRootType(config entity.Config, rootMetadata *entity.DataTypeMetadata) string {
+	template := config.SchemaIDTemplate
+	if template == "" {
+		template = "{base}/{type}"
+	}
+	return strings.NewReplacer(
+		"{base}", schemaIDBase(config, rootMetadata.Package),
+		"{package}", rootMetadata.Package,
+		"{package_name}", path.Base(rootMetadata.Package),
+		"{type}", rootMetadata.TypeName,
+		"{version}", config.SchemaIDVersion,
+	).Replace(template)
+}
+
+// identifyDefinitions gives definitions $id or $anchor, see entity.Config.DefinitionIDs
+func (c *MetaToSchemaConverter) identifyDefinitions(config entity.Config,
+	definitions *entity.OrderedMap[entity.DataType], types map[string]*entity.DataTypeMetadata) error {
+	if config.DefinitionIDs == entity.DefinitionNoID {
+		return nil
+	}
+	for _, dataTypeMetadata := range types {
+		definition, ok := definitions.Get(dataTypeMetadata.TypeName)
+		if !ok {
+			continue
+		}
+		// Only JSONSchema has identifier keywords
+		schema := &entity.JSONSchema{ObjectSchema: *definition.(*entity.ObjectSchema)}
+		switch config.DefinitionIDs {
+		case entity.DefinitionSchemaID:
+			id := c.getIdFromRootType(config, dataTypeMetadata)
+			if err := validateSchemaID(id); err != nil {
+				return fmt.Errorf("definition %s: %w", dataTypeMetadata.TypeName, err)
+			}
+			schema.SetID(id)
+		case entity.DefinitionAnchor:
+			anchor := dataTypeMetadata.TypeName
+			schema.Anchor = &anchor
+		default:
+			return fmt.Errorf("unknown definition id mode %s", config.DefinitionIDs)
+		}
+		definitions.Set(dataTypeMetadata.TypeName, schema)
+	}
+	return nil
+}
+
+// schemaIDBase returns base URI of schema ids of package
+func schemaIDBase(config entity.Config, packageName string) string {
+	if config.SchemaIDBase != "" {
+		return strings.TrimSuffix(config.SchemaIDBase, "/")
+	}
+	return fmt.Sprintf("https://%s", packageName)
+}
+
+func validateSchemaID(id string) error {
+	parsed, err := url.Parse(id)
+	if err != nil || !parsed.IsAbs() {
+		return fmt.Errorf("%w: %s", ErrInvalidSchemaID, id)
+	}
+	return nil
 }
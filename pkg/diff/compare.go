@@ -0,0 +1,643 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrReferenceNotFound = errors.New("reference not found")
+var ErrReferenceCycle = errors.New("references form cycle without schema")
+
+// annotations are keywords, that do not validate data
+var annotations = []string{"title", "description", "$comment", "examples", "default", "deprecated", "readOnly",
+	"writeOnly"}
+
+// identifiers are keywords, that identify schema or hold definitions, they are compared, where referenced
+var identifiers = []string{"$schema", "$id", "id", "$anchor", "$dynamicAnchor", "$defs", "definitions",
+	"$vocabulary"}
+
+// lowerBounds and upperBounds are numeric constraints, that are tightened by increase and decrease of value
+var lowerBounds = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties", "minContains"}
+var upperBounds = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties", "maxContains"}
+
+// valueConstraints are constraints without order, every change of them is tightening and relaxing
+var valueConstraints = []string{"pattern", "format", "contentEncoding", "contentMediaType"}
+
+// openSubschemas are keywords with subschema, whose absence is the same as schema true
+var openSubschemas = []string{"items", "additionalItems", "unevaluatedItems", "unevaluatedProperties",
+	"propertyNames"}
+
+type comparer struct {
+	oldRoot any
+	newRoot any
+	// stack are pairs of references being compared, comparison of recursive schemas stops at them
+	stack []string
+	// inverted is set in subschema of not, where tightening of subschema relaxes schema
+	inverted bool
+	changes  []Change
+}
+
+func (c *comparer) add(kind Kind, location string, breaksProducers, breaksConsumers bool, format string,
+	args ...any) {
+	if c.inverted {
+		breaksProducers, breaksConsumers = breaksConsumers, breaksProducers
+	}
+	c.changes = append(c.changes, Change{Kind: kind, Location: location, Message: fmt.Sprintf(format, args...),
+		BreaksProducers: breaksProducers, BreaksConsumers: breaksConsumers})
+}
+
+// compare compares subschemas at location
+func (c *comparer) compare(location string, oldSchema, newSchema any) error {
+	oldSchema, oldRef, err := resolve(c.oldRoot, oldSchema, nil)
+	if err != nil {
+		return fmt.Errorf("old schema at %s: %w", location, err)
+	}
+	newSchema, newRef, err := resolve(c.newRoot, newSchema, nil)
+	if err != nil {
+		return fmt.Errorf("new schema at %s: %w", location, err)
+	}
+	if oldRef != "" || newRef != "" {
+		refs := oldRef + " " + newRef
+		if slices.Contains(c.stack, refs) {
+			return nil
+		}
+		c.stack = append(c.stack, refs)
+		defer func() { c.stack = c.stack[:len(c.stack)-1] }()
+	}
+
+	oldObject, oldIsObject := normalize(oldSchema)
+	newObject, newIsObject := normalize(newSchema)
+	switch {
+	case !oldIsObject && !newIsObject:
+		return nil
+	case !oldIsObject:
+		c.add(KindSchemaChanged, location, false, true, "schema accepts values, old schema accepted nothing")
+		return nil
+	case !newIsObject:
+		c.add(KindSchemaChanged, location, true, false, "schema accepts nothing")
+		return nil
+	}
+	return c.compareKeywords(location, oldObject, newObject)
+}
+
+func (c *comparer) compareKeywords(location string, oldSchema, newSchema map[string]any) error {
+	c.compareTypes(location, oldSchema, newSchema)
+	c.compareEnums(location, oldSchema, newSchema)
+	for _, keyword := range lowerBounds {
+		c.compareBound(location, keyword, oldSchema[keyword], newSchema[keyword], true)
+	}
+	for _, keyword := range upperBounds {
+		c.compareBound(location, keyword, oldSchema[keyword], newSchema[keyword], false)
+	}
+	c.compareMultipleOf(location, oldSchema["multipleOf"], newSchema["multipleOf"])
+	c.compareUniqueItems(location, oldSchema["uniqueItems"], newSchema["uniqueItems"])
+	for _, keyword := range valueConstraints {
+		c.compareValueConstraint(location, keyword, oldSchema[keyword], newSchema[keyword])
+	}
+	c.compareRequired(location, oldSchema, newSchema)
+	if err := c.compareProperties(location, oldSchema, newSchema); err != nil {
+		return err
+	}
+	if err := c.compareAdditionalProperties(location, oldSchema, newSchema); err != nil {
+		return err
+	}
+	if err := c.compareSubschemas(location, oldSchema, newSchema); err != nil {
+		return err
+	}
+
+	for _, keyword := range sortedKeys(union(oldSchema, newSchema)) {
+		if handled(keyword) || reflect.DeepEqual(oldSchema[keyword], newSchema[keyword]) {
+			continue
+		}
+		if slices.Contains(annotations, keyword) {
+			c.add(KindAnnotationChanged, location, false, false, "%s changed from %s to %s", keyword,
+				describe(oldSchema[keyword]), describe(newSchema[keyword]))
+		} else {
+			c.add(KindSchemaChanged, location, true, true, "%s changed from %s to %s", keyword,
+				describe(oldSchema[keyword]), describe(newSchema[keyword]))
+		}
+	}
+	return nil
+}
+
+// handled reports whether keyword is compared by keyword specific rules
+func handled(keyword string) bool {
+	keywords := [][]string{identifiers, lowerBounds, upperBounds, valueConstraints, openSubschemas,
+		{"type", "enum", "const", "multipleOf", "uniqueItems", "required", "properties", "additionalProperties",
+			"patternProperties", "dependentSchemas", "contains", "not", "allOf", "anyOf", "oneOf", "prefixItems"}}
+	for _, group := range keywords {
+		if slices.Contains(group, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *comparer) compareTypes(location string, oldSchema, newSchema map[string]any) {
+	oldTypes, newTypes := types(oldSchema["type"]), types(newSchema["type"])
+	var removed, added bool
+	for _, oldType := range oldTypes {
+		removed = removed || !acceptsType(newTypes, oldType)
+	}
+	for _, newType := range newTypes {
+		added = added || !acceptsType(oldTypes, newType)
+	}
+	// Absent type accepts any type
+	removed = removed || (oldTypes == nil && newTypes != nil)
+	added = added || (newTypes == nil && oldTypes != nil)
+	if removed || added {
+		c.add(KindTypeChanged, location, removed, added, "type changed from %s to %s",
+			describeTypes(oldTypes), describeTypes(newTypes))
+	}
+}
+
+func (c *comparer) compareEnums(location string, oldSchema, newSchema map[string]any) {
+	oldValues, newValues := enumValues(oldSchema), enumValues(newSchema)
+	if oldValues == nil && newValues == nil {
+		return
+	}
+	if oldValues == nil {
+		c.add(KindEnumChanged, location, true, false, "values are restricted to %s", describe(newValues))
+		return
+	}
+	if newValues == nil {
+		c.add(KindEnumChanged, location, false, true, "values are not restricted to %s anymore",
+			describe(oldValues))
+		return
+	}
+
+	removed, added := difference(oldValues, newValues), difference(newValues, oldValues)
+	switch {
+	case len(removed) > 0 && len(added) > 0:
+		c.add(KindEnumChanged, location, true, true, "values %s removed, values %s added", describe(removed),
+			describe(added))
+	case len(removed) > 0:
+		c.add(KindEnumChanged, location, true, false, "values %s removed", describe(removed))
+	case len(added) > 0:
+		c.add(KindEnumChanged, location, false, true, "values %s added", describe(added))
+	}
+}
+
+// compareBound compares numeric constraint, that limits values from below, if lower is set, or from above
+func (c *comparer) compareBound(location, keyword string, oldValue, newValue any, lower bool) {
+	if oldValue == nil && newValue == nil {
+		return
+	}
+	oldNumber, oldIsNumber := oldValue.(float64)
+	newNumber, newIsNumber := newValue.(float64)
+	// Boolean exclusiveMinimum and exclusiveMaximum of draft 04 are compared as other keywords
+	if (oldValue != nil && !oldIsNumber) || (newValue != nil && !newIsNumber) {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			c.add(KindConstraintChanged, location, true, true, "%s changed from %s to %s", keyword,
+				describe(oldValue), describe(newValue))
+		}
+		return
+	}
+
+	var tightened, relaxed bool
+	switch {
+	case oldValue == nil:
+		tightened = true
+	case newValue == nil:
+		relaxed = true
+	case lower:
+		tightened, relaxed = newNumber > oldNumber, newNumber < oldNumber
+	default:
+		tightened, relaxed = newNumber < oldNumber, newNumber > oldNumber
+	}
+	c.addConstraintChange(location, keyword, oldValue, newValue, tightened, relaxed)
+}
+
+func (c *comparer) compareMultipleOf(location string, oldValue, newValue any) {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+	oldNumber, _ := oldValue.(float64)
+	newNumber, _ := newValue.(float64)
+	// Multiples of new value are multiples of old value, if new value is multiple of old value
+	tightened := oldValue == nil || (newValue != nil && isMultiple(newNumber, oldNumber))
+	relaxed := newValue == nil || (oldValue != nil && isMultiple(oldNumber, newNumber))
+	if !tightened && !relaxed {
+		tightened, relaxed = true, true
+	}
+	c.addConstraintChange(location, "multipleOf", oldValue, newValue, tightened, relaxed)
+}
+
+func (c *comparer) compareUniqueItems(location string, oldValue, newValue any) {
+	oldUnique, newUnique := oldValue == true, newValue == true
+	if oldUnique != newUnique {
+		c.addConstraintChange(location, "uniqueItems", oldValue, newValue, newUnique, oldUnique)
+	}
+}
+
+func (c *comparer) compareValueConstraint(location, keyword string, oldValue, newValue any) {
+	if !reflect.DeepEqual(oldValue, newValue) {
+		c.addConstraintChange(location, keyword, oldValue, newValue, newValue != nil, oldValue != nil)
+	}
+}
+
+func (c *comparer) addConstraintChange(location, keyword string, oldValue, newValue any, tightened, relaxed bool) {
+	switch {
+	case tightened && relaxed:
+		c.add(KindConstraintChanged, location, true, true, "%s changed from %s to %s", keyword,
+			describe(oldValue), describe(newValue))
+	case tightened:
+		c.add(KindConstraintChanged, location, true, false, "%s tightened from %s to %s", keyword,
+			describe(oldValue), describe(newValue))
+	case relaxed:
+		c.add(KindConstraintChanged, location, false, true, "%s relaxed from %s to %s", keyword,
+			describe(oldValue), describe(newValue))
+	}
+}
+
+func (c *comparer) compareRequired(location string, oldSchema, newSchema map[string]any) {
+	oldRequired, _ := oldSchema["required"].([]any)
+	newRequired, _ := newSchema["required"].([]any)
+	for _, name := range difference(newRequired, oldRequired) {
+		c.add(KindRequiredAdded, location+"/required", true, false, "property %s is required", describe(name))
+	}
+	for _, name := range difference(oldRequired, newRequired) {
+		c.add(KindRequiredRemoved, location+"/required", false, true, "property %s is not required anymore",
+			describe(name))
+	}
+}
+
+// compareProperties compares properties. Property, that is declared only in one schema, is compared with
+// additionalProperties of other schema.
+func (c *comparer) compareProperties(location string, oldSchema, newSchema map[string]any) error {
+	oldProperties, _ := oldSchema["properties"].(map[string]any)
+	newProperties, _ := newSchema["properties"].(map[string]any)
+	for _, name := range sortedKeys(union(oldProperties, newProperties)) {
+		propertyLocation := location + "/properties/" + escapePointer(name)
+		oldProperty, inOld := oldProperties[name]
+		newProperty, inNew := newProperties[name]
+		switch {
+		case inOld && inNew:
+			if err := c.compare(propertyLocation, oldProperty, newProperty); err != nil {
+				return err
+			}
+		case inNew:
+			additional, hasAdditional := oldSchema["additionalProperties"]
+			switch {
+			case additional == false:
+				c.add(KindPropertyAdded, propertyLocation, false, true, "property %q added, "+
+					"old schema does not allow additional properties", name)
+			case hasAdditional && !acceptsAll(additional):
+				c.add(KindPropertyAdded, propertyLocation, false, false, "property %q added", name)
+				if err := c.compare(propertyLocation, additional, newProperty); err != nil {
+					return err
+				}
+			default:
+				c.add(KindPropertyAdded, propertyLocation, !acceptsAll(newProperty), false,
+					"property %q added, old schema accepts any value of it", name)
+			}
+		default:
+			additional, hasAdditional := newSchema["additionalProperties"]
+			switch {
+			case additional == false:
+				c.add(KindPropertyRemoved, propertyLocation, true, false, "property %q removed, "+
+					"additional properties are not allowed", name)
+			case hasAdditional && !acceptsAll(additional):
+				c.add(KindPropertyRemoved, propertyLocation, false, false, "property %q removed", name)
+				if err := c.compare(propertyLocation, oldProperty, additional); err != nil {
+					return err
+				}
+			default:
+				c.add(KindPropertyRemoved, propertyLocation, false, !acceptsAll(oldProperty),
+					"property %q removed, schema accepts any value of it", name)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *comparer) compareAdditionalProperties(location string, oldSchema, newSchema map[string]any) error {
+	oldAdditional, hasOld := oldSchema["additionalProperties"]
+	newAdditional, hasNew := newSchema["additionalProperties"]
+	if !hasOld && !hasNew {
+		return nil
+	}
+	additionalLocation := location + "/additionalProperties"
+	switch {
+	case oldAdditional == false && newAdditional == false:
+		return nil
+	case newAdditional == false:
+		c.add(KindAdditionalPropertiesChanged, additionalLocation, true, false,
+			"additional properties are not allowed anymore")
+		return nil
+	case oldAdditional == false:
+		c.add(KindAdditionalPropertiesChanged, additionalLocation, false, true, "additional properties are allowed")
+		return nil
+	}
+	if !hasOld {
+		oldAdditional = true
+	}
+	if !hasNew {
+		newAdditional = true
+	}
+	return c.compare(additionalLocation, oldAdditional, newAdditional)
+}
+
+func (c *comparer) compareSubschemas(location string, oldSchema, newSchema map[string]any) error {
+	for _, keyword := range openSubschemas {
+		oldSubschema, hasOld := oldSchema[keyword]
+		newSubschema, hasNew := newSchema[keyword]
+		// Items of draft 04-07 can be array of schemas like prefixItems
+		_, oldIsArray := oldSubschema.([]any)
+		_, newIsArray := newSubschema.([]any)
+		if oldIsArray || newIsArray {
+			if err := c.compareSchemaArrays(location, keyword, oldSubschema, newSubschema, true); err != nil {
+				return err
+			}
+			continue
+		}
+		if !hasOld && !hasNew {
+			continue
+		}
+		if !hasOld {
+			oldSubschema = true
+		}
+		if !hasNew {
+			newSubschema = true
+		}
+		if err := c.compare(location+"/"+keyword, oldSubschema, newSubschema); err != nil {
+			return err
+		}
+	}
+
+	// Absent contains and not do not restrict values unlike schema true
+	for _, keyword := range []string{"contains", "not"} {
+		oldSubschema, hasOld := oldSchema[keyword]
+		newSubschema, hasNew := newSchema[keyword]
+		switch {
+		case hasOld && hasNew:
+			c.inverted = c.inverted != (keyword == "not")
+			err := c.compare(location+"/"+keyword, oldSubschema, newSubschema)
+			c.inverted = c.inverted != (keyword == "not")
+			if err != nil {
+				return err
+			}
+		case hasNew:
+			c.add(KindSchemaChanged, location, true, false, "%s added", keyword)
+		case hasOld:
+			c.add(KindSchemaChanged, location, false, true, "%s removed", keyword)
+		}
+	}
+
+	// Every schema of allOf and prefixItems restricts values, any schema of anyOf and oneOf allows values
+	if err := c.compareSchemaArrays(location, "allOf", oldSchema["allOf"], newSchema["allOf"], true); err != nil {
+		return err
+	}
+	if err := c.compareSchemaArrays(location, "prefixItems", oldSchema["prefixItems"], newSchema["prefixItems"],
+		true); err != nil {
+		return err
+	}
+	if err := c.compareSchemaArrays(location, "anyOf", oldSchema["anyOf"], newSchema["anyOf"], false); err != nil {
+		return err
+	}
+	if err := c.compareSchemaArrays(location, "oneOf", oldSchema["oneOf"], newSchema["oneOf"], false); err != nil {
+		return err
+	}
+
+	for _, keyword := range []string{"patternProperties", "dependentSchemas"} {
+		oldSchemas, _ := oldSchema[keyword].(map[string]any)
+		newSchemas, _ := newSchema[keyword].(map[string]any)
+		for _, name := range sortedKeys(union(oldSchemas, newSchemas)) {
+			oldSubschema, inOld := oldSchemas[name]
+			newSubschema, inNew := newSchemas[name]
+			switch {
+			case inOld && inNew:
+				if err := c.compare(location+"/"+keyword+"/"+escapePointer(name), oldSubschema,
+					newSubschema); err != nil {
+					return err
+				}
+			case inNew:
+				c.add(KindSchemaChanged, location+"/"+keyword, true, false, "%s %q added", keyword, name)
+			default:
+				c.add(KindSchemaChanged, location+"/"+keyword, false, true, "%s %q removed", keyword, name)
+			}
+		}
+	}
+	return nil
+}
+
+// compareSchemaArrays compares schemas of keyword by index. Every schema restricts values, if restricting is set,
+// otherwise every schema allows values.
+func (c *comparer) compareSchemaArrays(location, keyword string, oldValue, newValue any, restricting bool) error {
+	oldSchemas, _ := oldValue.([]any)
+	newSchemas, _ := newValue.([]any)
+	for i := 0; i < len(oldSchemas) && i < len(newSchemas); i++ {
+		if err := c.compare(fmt.Sprintf("%s/%s/%d", location, keyword, i), oldSchemas[i], newSchemas[i]); err != nil {
+			return err
+		}
+	}
+	// Absent anyOf and oneOf do not restrict values
+	if !restricting && (oldValue == nil || newValue == nil) {
+		if oldValue != nil || newValue != nil {
+			c.add(KindSchemaChanged, location, newValue != nil, oldValue != nil, "%s changed from %s to %s",
+				keyword, describe(oldValue), describe(newValue))
+		}
+		return nil
+	}
+	if len(newSchemas) > len(oldSchemas) {
+		c.add(KindSchemaChanged, location+"/"+keyword, restricting, !restricting, "%d schemas added to %s",
+			len(newSchemas)-len(oldSchemas), keyword)
+	}
+	if len(oldSchemas) > len(newSchemas) {
+		c.add(KindSchemaChanged, location+"/"+keyword, !restricting, restricting, "%d schemas removed from %s",
+			len(oldSchemas)-len(newSchemas), keyword)
+	}
+	return nil
+}
+
+// resolve follows local reference of schema. Keywords next to reference override keywords of referenced schema.
+// Reference of schema is returned, if schema is reference. Visited are references being resolved, schema,
+// that only references itself through them, is ErrReferenceCycle.
+func resolve(root, schema any, visited []string) (any, string, error) {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return schema, "", nil
+	}
+	ref, ok := object["$ref"].(string)
+	if !ok {
+		return schema, "", nil
+	}
+	pointer, ok := strings.CutPrefix(ref, "#")
+	// References to other documents and anchors are compared as other keywords
+	if !ok || (pointer != "" && !strings.HasPrefix(pointer, "/")) {
+		return schema, "", nil
+	}
+
+	if slices.Contains(visited, ref) {
+		return nil, "", fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(append(visited, ref), " -> "))
+	}
+	target, err := resolvePointer(root, pointer)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %s", ErrReferenceNotFound, ref)
+	}
+	target, _, err = resolve(root, target, append(visited, ref))
+	if err != nil {
+		return nil, "", err
+	}
+	targetObject, isObject := normalize(target)
+	if !isObject {
+		return target, ref, nil
+	}
+	resolved := make(map[string]any, len(targetObject)+len(object))
+	for keyword, value := range targetObject {
+		resolved[keyword] = value
+	}
+	for keyword, value := range object {
+		if keyword != "$ref" {
+			resolved[keyword] = value
+		}
+	}
+	return resolved, ref, nil
+}
+
+func resolvePointer(root any, pointer string) (any, error) {
+	if pointer == "" {
+		return root, nil
+	}
+	value := root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch container := value.(type) {
+		case map[string]any:
+			child, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", token)
+			}
+			value = child
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, fmt.Errorf("index %s not found", token)
+			}
+			value = container[index]
+		default:
+			return nil, fmt.Errorf("%s not found", token)
+		}
+	}
+	return value, nil
+}
+
+// normalize returns keywords of schema. Schema true has no keywords, schema false is not an object.
+func normalize(schema any) (map[string]any, bool) {
+	if schema == true {
+		return map[string]any{}, true
+	}
+	object, ok := schema.(map[string]any)
+	return object, ok
+}
+
+// acceptsAll reports whether schema accepts any value
+func acceptsAll(schema any) bool {
+	object, ok := normalize(schema)
+	if !ok {
+		return false
+	}
+	for keyword := range object {
+		if !slices.Contains(annotations, keyword) && !slices.Contains(identifiers, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// types returns types of type keyword or nil, if any type is allowed
+func types(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		result := make([]string, 0, len(value))
+		for _, dataType := range value {
+			if dataType, ok := dataType.(string); ok {
+				result = append(result, dataType)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// acceptsType reports whether types accept values of dataType. Nil types accept any type.
+func acceptsType(types []string, dataType string) bool {
+	if types == nil || slices.Contains(types, dataType) {
+		return true
+	}
+	// Integers are numbers
+	return dataType == "integer" && slices.Contains(types, "number")
+}
+
+func describeTypes(types []string) string {
+	if types == nil {
+		return "any"
+	}
+	return strings.Join(types, ", ")
+}
+
+// enumValues returns values of enum or const, nil if any value is allowed
+func enumValues(schema map[string]any) []any {
+	if value, ok := schema["const"]; ok {
+		return []any{value}
+	}
+	values, _ := schema["enum"].([]any)
+	return values
+}
+
+// difference returns values, that are not in other values
+func difference(values, otherValues []any) []any {
+	var result []any
+	for _, value := range values {
+		if !slices.ContainsFunc(otherValues, func(otherValue any) bool {
+			return reflect.DeepEqual(value, otherValue)
+		}) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func union[T any](values, otherValues map[string]T) map[string]bool {
+	result := make(map[string]bool, len(values)+len(otherValues))
+	for key := range values {
+		result[key] = true
+	}
+	for key := range otherValues {
+		result[key] = true
+	}
+	return result
+}
+
+// isMultiple reports whether value is multiple of divisor
+func isMultiple(value, divisor float64) bool {
+	if divisor == 0 {
+		return false
+	}
+	quotient := value / divisor
+	return math.Abs(quotient-math.Round(quotient)) < 1e-9
+}
+
+// describe returns JSON of value for messages, none for absent value
+func describe(value any) string {
+	if value == nil {
+		return "none"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,143 @@
+// Package diff compares two versions of JSON Schema and classifies changes.
+//
+// A change breaks producers, if new schema rejects some data valid by old schema: producers, that write data
+// by old schema, must change. A change breaks consumers, if new schema accepts some data invalid by old schema:
+// consumers, that read data by old schema, can receive unexpected data. Changes of annotations like
+// description break nobody.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"sort"
+	"strings"
+)
+
+// Kind is kind of change
+type Kind string
+
+const (
+	KindTypeChanged                 Kind = "type-changed"
+	KindPropertyAdded               Kind = "property-added"
+	KindPropertyRemoved             Kind = "property-removed"
+	KindRequiredAdded               Kind = "required-added"
+	KindRequiredRemoved             Kind = "required-removed"
+	KindEnumChanged                 Kind = "enum-changed"
+	KindConstraintChanged           Kind = "constraint-changed"
+	KindAdditionalPropertiesChanged Kind = "additional-properties-changed"
+	KindSchemaChanged               Kind = "schema-changed"
+	KindAnnotationChanged           Kind = "annotation-changed"
+)
+
+// Change is a difference between old and new schema
+type Change struct {
+	Kind Kind `json:"kind"`
+	// Location is JSON Pointer of changed schema, e.g. /properties/address/properties/zip.
+	// References are followed, so change of definition is reported at every location, where it is used.
+	Location        string `json:"location"`
+	Message         string `json:"message"`
+	BreaksProducers bool   `json:"breaksProducers"`
+	BreaksConsumers bool   `json:"breaksConsumers"`
+}
+
+// Breaking reports whether change breaks producers or consumers
+func (c Change) Breaking() bool {
+	return c.BreaksProducers || c.BreaksConsumers
+}
+
+func (c Change) String() string {
+	var compatibility string
+	switch {
+	case c.BreaksProducers && c.BreaksConsumers:
+		compatibility = "breaks producers and consumers"
+	case c.BreaksProducers:
+		compatibility = "breaks producers"
+	case c.BreaksConsumers:
+		compatibility = "breaks consumers"
+	default:
+		compatibility = "compatible"
+	}
+	return fmt.Sprintf("[%s] #%s: %s", compatibility, c.Location, c.Message)
+}
+
+// Report contains changes of new schema. Report is marshaled to JSON for tools like CI.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking reports whether any change breaks producers or consumers
+func (r *Report) Breaking() bool {
+	return r.BreaksProducers() || r.BreaksConsumers()
+}
+
+// BreaksProducers reports whether new schema rejects some data valid by old schema
+func (r *Report) BreaksProducers() bool {
+	for _, change := range r.Changes {
+		if change.BreaksProducers {
+			return true
+		}
+	}
+	return false
+}
+
+// BreaksConsumers reports whether new schema accepts some data invalid by old schema
+func (r *Report) BreaksConsumers() bool {
+	for _, change := range r.Changes {
+		if change.BreaksConsumers {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns human-readable report with a change per line
+func (r *Report) String() string {
+	if len(r.Changes) == 0 {
+		return "no changes\n"
+	}
+	var builder strings.Builder
+	for _, change := range r.Changes {
+		builder.WriteString(change.String())
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// Compare reports changes of newSchema against oldSchema
+func Compare(oldSchema, newSchema *entity.JSONSchema) (*Report, error) {
+	oldData, err := json.Marshal(oldSchema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal old schema: %w", err)
+	}
+	newData, err := json.Marshal(newSchema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal new schema: %w", err)
+	}
+	return CompareJSON(oldData, newData)
+}
+
+// CompareJSON reports changes of newData schema against oldData schema
+func CompareJSON(oldData, newData []byte) (*Report, error) {
+	var oldDocument, newDocument any
+	if err := json.Unmarshal(oldData, &oldDocument); err != nil {
+		return nil, fmt.Errorf("unmarshal old schema: %w", err)
+	}
+	if err := json.Unmarshal(newData, &newDocument); err != nil {
+		return nil, fmt.Errorf("unmarshal new schema: %w", err)
+	}
+	c := &comparer{oldRoot: oldDocument, newRoot: newDocument}
+	if err := c.compare("", oldDocument, newDocument); err != nil {
+		return nil, err
+	}
+	return &Report{Changes: c.changes}, nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -15,4 +15,36 @@ type Config struct {
 	// The default is to use the key's name as is, or the json tag if present.
 	// If a json tag is present, KeyNamer will receive the tag's name as an argument, not the original key name.
 	KeyNamer func(string) string
+
+	// SortProperties orders properties alphabetically. Properties are in order of struct fields by default.
+	SortProperties bool
+
+	// ExternalRefs references structs declared in other package than root type by $id of their own schema,
+	// e.g. https://github.com/x/additional/InnerSettings, instead of copying them into $defs.
+	// Schemas of such structs are generated separately, e.g. by GenerateEach for both packages.
+	ExternalRefs bool
+
+	// SchemaIDBase is base URI of schema $id, e.g. https://schemas.ourco.io/v2. https://<package path> by default.
+	SchemaIDBase string
+	// SchemaIDTemplate is template of schema $id with placeholders {base}, {package} (package path),
+	// {package_name}, {type} and {version}, e.g. {base}/{package_name}/{type}.json. {base}/{type} by default.
+	SchemaIDTemplate string
+	// SchemaIDVersion replaces {version} placeholder of SchemaIDTemplate
+	SchemaIDVersion string
+	// OmitSchemaID omits $id of root schema. References of ExternalRefs still use $id of referenced schemas.
+	OmitSchemaID bool
+	// DefinitionIDs gives definitions their own $id or $anchor for linking from other schemas
+	DefinitionIDs DefinitionIDMode
 }
+
+// DefinitionIDMode defines identifier of definitions
+type DefinitionIDMode string
+
+const (
+	// DefinitionNoID does not identify definitions, they are referenced by JSON Pointer
+	DefinitionNoID DefinitionIDMode = ""
+	// DefinitionSchemaID sets $id of definition to $id of type schema, see Config.SchemaIDTemplate
+	DefinitionSchemaID DefinitionIDMode = "id"
+	// DefinitionAnchor sets $anchor of definition to type name, e.g. https://<package path>/Root#InnerSettings
+	DefinitionAnchor DefinitionIDMode = "anchor"
+)
@@ -1,6 +1,10 @@
 package entity
 
-import "reflect"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
 
 type DataType interface {
 	// IsType return true, if schema contains type
@@ -11,9 +15,9 @@ type DataType interface {
 
 // BaseSchema represents common fields for all schemas
 type BaseSchema[T any] struct {
-	Type        JSONSchemaType `json:"type,omitempty"`        // All DraftVersion
 	Title       *string        `json:"title,omitempty"`       // All DraftVersion
 	Description *string        `json:"description,omitempty"` // All DraftVersion
+	Type        JSONSchemaType `json:"type,omitempty"`        // All DraftVersion
 	Default     *T             `json:"default,omitempty"`     // DraftVersion-06 and later
 	Examples    []*T           `json:"examples,omitempty"`    // DraftVersion-06 and later
 	Const       *T             `json:"const,omitempty"`       // DraftVersion-06 and later
@@ -40,11 +44,11 @@ func (s *BaseSchema[T]) IsTypes(dataTypes []JSONSchemaDataType) bool {
 // ObjectSchema represents a schema for object values
 type ObjectSchema struct {
 	BaseSchema[map[string]any]
-	Properties           map[string]DataType    `json:"properties,omitempty"`           // All DraftVersion
-	PatternProperties    map[string]DataType    `json:"patternProperties,omitempty"`    // DraftVersion-04 and later
+	Properties           *OrderedMap[DataType]  `json:"properties,omitempty"`           // All DraftVersion
+	PatternProperties    *OrderedMap[DataType]  `json:"patternProperties,omitempty"`    // DraftVersion-04 and later
 	AdditionalProperties *AdditionalProperties  `json:"additionalProperties,omitempty"` // All DraftVersion
-	MaxProperties        *int                   `json:"maxProperties,omitempty"`        // All DraftVersion
 	MinProperties        *int                   `json:"minProperties,omitempty"`        // All DraftVersion
+	MaxProperties        *int                   `json:"maxProperties,omitempty"`        // All DraftVersion
 	Required             []string               `json:"required,omitempty"`             // All DraftVersion
 	Dependencies         map[string]*Dependency `json:"dependencies,omitempty"`         // DraftVersion-04, DraftVersion-06, DraftVersion-07
 	DependentSchemas     map[string]*JSONSchema `json:"dependentSchemas,omitempty"`     // DraftVersion-2019-09 and later
@@ -62,28 +66,30 @@ type ObjectSchema struct {
 	Not   *JSONSchema   `json:"not,omitempty"`   // DraftVersion-04 and later
 }
 
-// JSONSchema represents the top-level structure of a JSON Schema
+// JSONSchema represents the top-level structure of a JSON Schema.
+// Fields are declared in output order: $schema and $id first, $defs last.
 type JSONSchema struct {
-	DeprecatedID *string             `json:"id,omitempty"`          // DraftVersion-04 and later
-	ID           *string             `json:"$id,omitempty"`         // DraftVersion-06 and later
-	Schema       *DraftVersion       `json:"$schema,omitempty"`     // All DraftVersion
-	Defs         map[string]DataType `json:"$defs,omitempty"`       // DraftVersion-06 and later
-	Ref          *string             `json:"$ref,omitempty"`        // All DraftVersion
-	DynamicRef   *string             `json:"$dynamicRef,omitempty"` // DraftVersion-2019-09 and later
-	Anchor       *string             `json:"$anchor,omitempty"`     // DraftVersion-2019-09 and later
-	Vocabulary   map[string]string   `json:"$vocabulary,omitempty"` // DraftVersion-2019-09 and later
+	Schema       *DraftVersion   `json:"$schema,omitempty"`     // All DraftVersion
+	ID           *string         `json:"$id,omitempty"`         // DraftVersion-06 and later
+	DeprecatedID *string         `json:"id,omitempty"`          // DraftVersion-04 and later
+	Ref          *string         `json:"$ref,omitempty"`        // All DraftVersion
+	DynamicRef   *string         `json:"$dynamicRef,omitempty"` // DraftVersion-2019-09 and later
+	Anchor       *string         `json:"$anchor,omitempty"`     // DraftVersion-2019-09 and later
+	Vocabulary   map[string]bool `json:"$vocabulary,omitempty"` // DraftVersion-2019-09 and later
 
 	ObjectSchema
+
+	Defs *OrderedMap[DataType] `json:"$defs,omitempty"` // DraftVersion-06 and later
 }
 
 // NumericSchema represents a base schema for numeric values
 type NumericSchema[T any] struct {
 	BaseSchema[T]
 	MultipleOf       *T `json:"multipleOf,omitempty"`       // DraftVersion-06 and later
-	Maximum          *T `json:"maximum,omitempty"`          // All DraftVersion
-	ExclusiveMaximum *T `json:"exclusiveMaximum,omitempty"` // DraftVersion-06 and later
 	Minimum          *T `json:"minimum,omitempty"`          // All DraftVersion
 	ExclusiveMinimum *T `json:"exclusiveMinimum,omitempty"` // DraftVersion-06 and later
+	Maximum          *T `json:"maximum,omitempty"`          // All DraftVersion
+	ExclusiveMaximum *T `json:"exclusiveMaximum,omitempty"` // DraftVersion-06 and later
 }
 
 // NumberSchema represents a schema for number values
@@ -99,8 +105,8 @@ type IntegerSchema struct {
 // StringSchema represents a schema for string values
 type StringSchema struct {
 	BaseSchema[string]
-	MaxLength        *int        `json:"maxLength,omitempty"`        // All DraftVersion
 	MinLength        *int        `json:"minLength,omitempty"`        // All DraftVersion
+	MaxLength        *int        `json:"maxLength,omitempty"`        // All DraftVersion
 	Pattern          *string     `json:"pattern,omitempty"`          // All DraftVersion
 	Format           *string     `json:"format,omitempty"`           // DraftVersion-04 and later
 	ContentMediaType *string     `json:"contentMediaType,omitempty"` // DraftVersion-07 and later
@@ -124,8 +130,8 @@ type ArraySchema struct {
 	Items            *JSONSchema   `json:"items,omitempty"`            // All DraftVersion
 	PrefixItems      []*JSONSchema `json:"prefixItems,omitempty"`      // DraftVersion-2020-12 and later
 	Contains         *JSONSchema   `json:"contains,omitempty"`         // DraftVersion-06 and later
-	MaxItems         *int          `json:"maxItems,omitempty"`         // All DraftVersion
 	MinItems         *int          `json:"minItems,omitempty"`         // All DraftVersion
+	MaxItems         *int          `json:"maxItems,omitempty"`         // All DraftVersion
 	UniqueItems      *bool         `json:"uniqueItems,omitempty"`      // DraftVersion-04 and later
 	MinContains      *int          `json:"minContains,omitempty"`      // DraftVersion-2019-09 and later
 	MaxContains      *int          `json:"maxContains,omitempty"`      // DraftVersion-2019-09 and later
@@ -146,6 +152,183 @@ type Dependency struct {
 	SchemaDependency     *JSONSchema `json:"-"` // DraftVersion-04, DraftVersion-06, DraftVersion-07
 }
 
+// MarshalJSON marshals additionalProperties as boolean or as schema
+func (p AdditionalProperties) MarshalJSON() ([]byte, error) {
+	if p.Schema != nil {
+		return json.Marshal(p.Schema)
+	}
+	if p.Bool != nil {
+		return json.Marshal(*p.Bool)
+	}
+	return []byte("{}"), nil
+}
+
+// MarshalJSON marshals dependency as array of properties or as schema
+func (d Dependency) MarshalJSON() ([]byte, error) {
+	if d.SchemaDependency != nil {
+		return json.Marshal(d.SchemaDependency)
+	}
+	if d.PropertyDependencies == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(d.PropertyDependencies)
+}
+
+// UnmarshalJSON unmarshals additionalProperties from boolean or from schema
+func (p *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		p.Bool = &value
+		return nil
+	}
+	p.Schema = new(JSONSchema)
+	return json.Unmarshal(data, p.Schema)
+}
+
+// UnmarshalJSON unmarshals dependency from array of properties or from schema
+func (d *Dependency) UnmarshalJSON(data []byte) error {
+	var properties []string
+	if err := json.Unmarshal(data, &properties); err == nil {
+		d.PropertyDependencies = properties
+		return nil
+	}
+	d.SchemaDependency = new(JSONSchema)
+	return json.Unmarshal(data, d.SchemaDependency)
+}
+
+// UnmarshalJSON unmarshals schema document. Subschemas of properties and $defs are unmarshaled into
+// schema of their type, see UnmarshalDataType.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		// Boolean schema: true allows everything, false allows nothing
+		*s = JSONSchema{}
+		if !value {
+			s.Not = &JSONSchema{}
+		}
+		return nil
+	}
+
+	var header struct {
+		DeprecatedID *string         `json:"id"`
+		ID           *string         `json:"$id"`
+		Schema       *DraftVersion   `json:"$schema"`
+		Defs         json.RawMessage `json:"$defs"`
+		Ref          *string         `json:"$ref"`
+		DynamicRef   *string         `json:"$dynamicRef"`
+		Anchor       *string         `json:"$anchor"`
+		Vocabulary   map[string]bool `json:"$vocabulary"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	defs, err := unmarshalDataTypes(header.Defs)
+	if err != nil {
+		return fmt.Errorf("$defs: %w", err)
+	}
+	*s = JSONSchema{
+		DeprecatedID: header.DeprecatedID,
+		ID:           header.ID,
+		Schema:       header.Schema,
+		Defs:         defs,
+		Ref:          header.Ref,
+		DynamicRef:   header.DynamicRef,
+		Anchor:       header.Anchor,
+		Vocabulary:   header.Vocabulary,
+	}
+	return json.Unmarshal(data, &s.ObjectSchema)
+}
+
+// UnmarshalJSON unmarshals object schema. Subschemas of properties are unmarshaled into
+// schema of their type, see UnmarshalDataType.
+func (s *ObjectSchema) UnmarshalJSON(data []byte) error {
+	type objectSchema ObjectSchema
+	var raw struct {
+		*objectSchema
+		Properties        json.RawMessage `json:"properties"`
+		PatternProperties json.RawMessage `json:"patternProperties"`
+	}
+	raw.objectSchema = (*objectSchema)(s)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if s.Properties, err = unmarshalDataTypes(raw.Properties); err != nil {
+		return fmt.Errorf("properties: %w", err)
+	}
+	if s.PatternProperties, err = unmarshalDataTypes(raw.PatternProperties); err != nil {
+		return fmt.Errorf("patternProperties: %w", err)
+	}
+	return nil
+}
+
+// generalKeywords are keywords, that only JSONSchema can hold
+var generalKeywords = []string{"id", "$id", "$schema", "$defs", "$ref", "$dynamicRef", "$anchor", "$vocabulary",
+	"allOf", "anyOf", "oneOf", "not", "if", "then", "else"}
+
+// UnmarshalDataType unmarshals schema into the schema of its type: StringSchema for string type,
+// ObjectSchema for object type and so on. Schemas with references, composition, several types or
+// without type are unmarshaled into JSONSchema.
+func UnmarshalDataType(data []byte) (DataType, error) {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		// Boolean schema
+		schema := new(JSONSchema)
+		return schema, json.Unmarshal(data, schema)
+	}
+
+	var dataType DataType = new(JSONSchema)
+	if !hasAnyKeyword(keywords, generalKeywords) {
+		var schemaType JSONSchemaType
+		if rawType, ok := keywords["type"]; ok {
+			if err := json.Unmarshal(rawType, &schemaType); err != nil {
+				return nil, err
+			}
+		}
+		if len(schemaType) == 1 {
+			switch schemaType[0] {
+			case JSONSchemaString:
+				dataType = new(StringSchema)
+			case JSONSchemaNumber:
+				dataType = new(NumberSchema)
+			case JSONSchemaInteger:
+				dataType = new(IntegerSchema)
+			case JSONSchemaBoolean:
+				dataType = new(BooleanSchema)
+			case JSONSchemaNull:
+				dataType = new(NullSchema)
+			case JSONSchemaArray:
+				dataType = new(ArraySchema)
+			case JSONSchemaObject:
+				dataType = new(ObjectSchema)
+			}
+		}
+	}
+	if err := json.Unmarshal(data, dataType); err != nil {
+		return nil, err
+	}
+	return dataType, nil
+}
+
+// unmarshalDataTypes unmarshals object of schemas keeping order of keys
+func unmarshalDataTypes(raw json.RawMessage) (*OrderedMap[DataType], error) {
+	if raw == nil || string(raw) == "null" {
+		return nil, nil
+	}
+	return unmarshalOrdered(raw, func(data json.RawMessage) (DataType, error) {
+		return UnmarshalDataType(data)
+	})
+}
+
+func hasAnyKeyword(keywords map[string]json.RawMessage, names []string) bool {
+	for _, name := range names {
+		if _, ok := keywords[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // NewAdditionalPropertiesBool creates a new AdditionalProperties instance
 func NewAdditionalPropertiesBool(value bool) *AdditionalProperties {
 	return &AdditionalProperties{Bool: &value}
@@ -203,9 +386,9 @@ func (s *JSONSchema) SetSchema(draft DraftVersion) *JSONSchema {
 
 func (s *JSONSchema) AddDefinition(name string, schema DataType) *JSONSchema {
 	if s.Defs == nil {
-		s.Defs = make(map[string]DataType)
+		s.Defs = NewOrderedMap[DataType]()
 	}
-	s.Defs[name] = schema
+	s.Defs.Set(name, schema)
 	return s
 }
 
@@ -214,6 +397,11 @@ func (s *JSONSchema) SetRef(ref string) *JSONSchema {
 	return s
 }
 
+func (s *JSONSchema) SetType(types ...JSONSchemaDataType) *JSONSchema {
+	s.Type = types
+	return s
+}
+
 func NewNumberSchema() *NumberSchema {
 	schema := new(NumberSchema)
 	schema.Type = JSONSchemaType{JSONSchemaNumber}
@@ -265,9 +453,9 @@ func NewObjectSchema() *ObjectSchema {
 
 func (s *ObjectSchema) AddProperty(name string, schema DataType) *ObjectSchema {
 	if s.Properties == nil {
-		s.Properties = make(map[string]DataType)
+		s.Properties = NewOrderedMap[DataType]()
 	}
-	s.Properties[name] = schema
+	s.Properties.Set(name, schema)
 	return s
 }
 
@@ -281,6 +469,17 @@ func (s *ObjectSchema) SetRequired(fields ...string) *ObjectSchema {
 	return s
 }
 
+func (s *ObjectSchema) SetAdditionalProperties(schema *JSONSchema) *ObjectSchema {
+	s.AdditionalProperties = NewAdditionalPropertiesSchema(schema)
+	return s
+}
+
+func NewArraySchema(items *JSONSchema) *ArraySchema {
+	schema := &ArraySchema{Items: items}
+	schema.Type = JSONSchemaType{JSONSchemaArray}
+	return schema
+}
+
 func NewBooleanSchema() *BooleanSchema {
 	schema := new(BooleanSchema)
 	schema.Type = JSONSchemaType{JSONSchemaBoolean}
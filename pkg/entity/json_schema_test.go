@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestJSONSchemaUnmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Generated schema",
+			input: "../../tests/output/settings.json",
+		},
+		{
+			name:  "Generated bundle",
+			input: "../../tests/output/base_bundle.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := os.ReadFile(tt.input)
+			require.NoError(t, err)
+
+			var schema JSONSchema
+			require.NoError(t, json.Unmarshal(expected, &schema))
+			output, err := json.Marshal(&schema)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(output))
+		})
+	}
+}
+
+func TestUnmarshalDataType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected DataType
+	}{
+		{
+			name:     "String",
+			input:    `{"type": "string", "enum": ["a", "b"], "maxLength": 1}`,
+			expected: &StringSchema{},
+		},
+		{
+			name:     "Integer",
+			input:    `{"type": "integer", "minimum": 0}`,
+			expected: &IntegerSchema{},
+		},
+		{
+			name:     "Array",
+			input:    `{"type": "array", "items": {"$ref": "#/$defs/a"}}`,
+			expected: &ArraySchema{},
+		},
+		{
+			name:     "Object",
+			input:    `{"type": "object", "properties": {"a": {"type": "boolean"}}, "additionalProperties": false}`,
+			expected: &ObjectSchema{},
+		},
+		{
+			name:     "Reference",
+			input:    `{"$ref": "#/$defs/a"}`,
+			expected: &JSONSchema{},
+		},
+		{
+			name:     "Several types",
+			input:    `{"type": ["string", "null"]}`,
+			expected: &JSONSchema{},
+		},
+		{
+			name:     "Boolean schema",
+			input:    `true`,
+			expected: &JSONSchema{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataType, err := UnmarshalDataType([]byte(tt.input))
+			require.NoError(t, err)
+			assert.IsType(t, tt.expected, dataType)
+
+			output, err := json.Marshal(dataType)
+			require.NoError(t, err)
+			if tt.input == "true" {
+				assert.JSONEq(t, `{}`, string(output))
+				return
+			}
+			assert.JSONEq(t, tt.input, string(output))
+		})
+	}
+}
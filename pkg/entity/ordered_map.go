@@ -0,0 +1,213 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// OrderedMap is a map, that keeps insertion order of keys. It is marshaled to JSON object with keys in that order.
+// Methods can be called on nil map, except Set.
+type OrderedMap[V any] struct {
+	keys   []string
+	values map[string]V
+}
+
+func NewOrderedMap[V any]() *OrderedMap[V] {
+	return &OrderedMap[V]{values: make(map[string]V)}
+}
+
+// Set adds value to the end of map or replaces value of existing key keeping its position
+func (m *OrderedMap[V]) Set(key string, value V) *OrderedMap[V] {
+	if m.values == nil {
+		m.values = make(map[string]V)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+	return m
+}
+
+// Get returns value of key
+func (m *OrderedMap[V]) Get(key string) (V, bool) {
+	if m == nil {
+		var empty V
+		return empty, false
+	}
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Delete removes key from map
+func (m *OrderedMap[V]) Delete(key string) {
+	if m == nil {
+		return
+	}
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len returns count of keys
+func (m *OrderedMap[V]) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.keys)
+}
+
+// Keys returns keys in order
+func (m *OrderedMap[V]) Keys() []string {
+	if m == nil {
+		return nil
+	}
+	return append([]string(nil), m.keys...)
+}
+
+// SortKeys orders keys alphabetically
+func (m *OrderedMap[V]) SortKeys() {
+	if m == nil {
+		return
+	}
+	sort.Strings(m.keys)
+}
+
+// MarshalJSON marshals map as JSON object with keys in order
+func (m *OrderedMap[V]) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueData, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON unmarshals JSON object keeping order of its keys
+func (m *OrderedMap[V]) UnmarshalJSON(data []byte) error {
+	result, err := unmarshalOrdered(data, func(raw json.RawMessage) (V, error) {
+		var value V
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	})
+	if err != nil {
+		return err
+	}
+	*m = *result
+	return nil
+}
+
+// unmarshalOrdered unmarshals JSON object keeping order of its keys. Values are unmarshaled by unmarshalValue.
+func unmarshalOrdered[V any](data []byte, unmarshalValue func(raw json.RawMessage) (V, error)) (*OrderedMap[V], error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected JSON object, got %v", token)
+	}
+
+	result := NewOrderedMap[V]()
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		var raw json.RawMessage
+		if err = decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+		value, err := unmarshalValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		result.Set(key, value)
+	}
+	return result, nil
+}
+
+// UnmarshalOrderedJSON unmarshals any JSON value keeping order of keys in all its objects. Objects are
+// unmarshaled into *OrderedMap[any], arrays into []any and numbers into json.Number, so they are kept as is.
+func UnmarshalOrderedJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeOrdered(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+// decodeOrdered decodes next JSON value of decoder
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := NewOrderedMap[any]()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			obj.Set(key.(string), value)
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case '[':
+		array := make([]any, 0)
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	}
+	return nil, fmt.Errorf("unexpected delimiter %s", delim)
+}
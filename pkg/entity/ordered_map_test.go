@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[int]()
+	m.Set("b", 1).Set("a", 2).Set("c", 3)
+	// Existing key keeps its position
+	m.Set("b", 4)
+	m.Delete("a")
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.Equal(t, `{"b":4,"c":3}`, string(data))
+
+	m.Set("a", 5)
+	m.SortKeys()
+	require.Equal(t, []string{"a", "b", "c"}, m.Keys())
+
+	var empty *OrderedMap[int]
+	require.Equal(t, 0, empty.Len())
+	_, ok := empty.Get("a")
+	require.False(t, ok)
+}
+
+func TestOrderedMapUnmarshal(t *testing.T) {
+	input := `{"z":{"type":"string"},"a":{"type":"integer"},"m":{"$ref":"#/$defs/M"}}`
+
+	var schema JSONSchema
+	require.NoError(t, json.Unmarshal([]byte(`{"properties":`+input+`}`), &schema))
+	require.Equal(t, []string{"z", "a", "m"}, schema.Properties.Keys())
+
+	data, err := json.Marshal(schema.Properties)
+	require.NoError(t, err)
+	require.Equal(t, input, string(data))
+}
+
+func TestUnmarshalOrderedJSON(t *testing.T) {
+	input := `{"z":{"b":[1,{"y":null,"x":true}],"a":1.50},"a":"text"}`
+
+	value, err := UnmarshalOrderedJSON([]byte(input))
+	require.NoError(t, err)
+	obj := value.(*OrderedMap[any])
+	require.Equal(t, []string{"z", "a"}, obj.Keys())
+	z, _ := obj.Get("z")
+	require.Equal(t, []string{"b", "a"}, z.(*OrderedMap[any]).Keys())
+
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	require.Equal(t, input, string(data))
+
+	_, err = UnmarshalOrderedJSON([]byte(`{} {}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// subschemaKeywords are keywords, whose values are schemas
+var subschemaKeywords = []string{"additionalProperties", "additionalItems", "contains", "propertyNames",
+	"if", "then", "else", "not", "unevaluatedItems", "unevaluatedProperties", "contentSchema", "items"}
+
+// subschemaArrayKeywords are keywords, whose values are arrays of schemas
+var subschemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+
+// subschemaMapKeywords are keywords, whose values are objects with schemas
+var subschemaMapKeywords = []string{"properties", "patternProperties", "$defs", "definitions",
+	"dependentSchemas", "dependencies"}
+
+// EachSubschema calls fn for every subschema in keywords of schema decoded from JSON into map[string]any or
+// *OrderedMap[any], see UnmarshalOrderedJSON. Pointer is JSON Pointer of subschema relative to schema.
+// Subschemas in objects like properties are visited in order of names: sorted for map[string]any and
+// in order of keys for OrderedMap. Values, that are not schemas, like property dependencies are skipped.
+func EachSubschema(schema any, fn func(subschema any, pointer string) error) error {
+	_, get, ok := rawObject(schema)
+	if !ok {
+		return nil
+	}
+	for _, keyword := range subschemaKeywords {
+		if value := get(keyword); isRawSchema(value) {
+			if err := fn(value, "/"+escapePointer(keyword)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range subschemaArrayKeywords {
+		values, _ := get(keyword).([]any)
+		for i, value := range values {
+			if !isRawSchema(value) {
+				continue
+			}
+			if err := fn(value, "/"+keyword+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range subschemaMapKeywords {
+		names, getValue, _ := rawObject(get(keyword))
+		for _, name := range names {
+			value := getValue(name)
+			if !isRawSchema(value) {
+				continue
+			}
+			if err := fn(value, "/"+escapePointer(keyword)+"/"+escapePointer(name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rawObject returns keys and values of JSON object decoded into map[string]any or *OrderedMap[any]
+func rawObject(value any) (keys []string, get func(key string) any, ok bool) {
+	switch obj := value.(type) {
+	case map[string]any:
+		keys = make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys, func(key string) any { return obj[key] }, true
+	case *OrderedMap[any]:
+		return obj.Keys(), func(key string) any {
+			value, _ := obj.Get(key)
+			return value
+		}, true
+	}
+	return nil, nil, false
+}
+
+// isRawSchema reports whether value decoded from JSON is schema: object or boolean
+func isRawSchema(value any) bool {
+	switch value.(type) {
+	case map[string]any, *OrderedMap[any], bool:
+		return true
+	}
+	return false
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -5,6 +5,9 @@ import "fmt"
 type JsonSchemaMetadata struct {
 	Types map[string]*DataTypeMetadata
 	Root  *DataTypeMetadata
+	// Roots contains all root types, when several types were parsed at once.
+	// Root is not set in this case.
+	Roots []*DataTypeMetadata
 }
 
 type DataTypeMetadata struct {
@@ -22,6 +25,17 @@ func NewJsonSchemaMetadata() *JsonSchemaMetadata {
 	return &JsonSchemaMetadata{Types: make(map[string]*DataTypeMetadata), Root: nil}
 }
 
+// RootTypes returns all root types of metadata
+func (m *JsonSchemaMetadata) RootTypes() []*DataTypeMetadata {
+	if len(m.Roots) > 0 {
+		return m.Roots
+	}
+	if m.Root != nil {
+		return []*DataTypeMetadata{m.Root}
+	}
+	return nil
+}
+
 func NewDataTypeRefMetadata(ref *DataTypeMetadata) *DataTypeMetadata {
 	return &DataTypeMetadata{
 		Ref: ref,
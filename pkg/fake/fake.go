@@ -0,0 +1,122 @@
+// Package fake generates JSON instances, that are valid by JSON Schema, e.g. for fixtures, examples and
+// round-trip tests. Instances are deterministic for the same seed and schema.
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"math/rand"
+)
+
+var ErrUnsatisfiable = errors.New("instance of schema can not be generated")
+var ErrReferenceNotFound = errors.New("reference not found")
+
+// maxAttempts limits generation of instance, that is valid by schema
+const maxAttempts = 20
+
+// Generator generates instances of schemas
+type Generator struct {
+	rand                *rand.Rand
+	maxDepth            int
+	optionalProbability float64
+	maxItems            int
+}
+
+// Option configures Generator
+type Option func(g *Generator)
+
+// WithMaxDepth limits nesting of objects and arrays, 5 by default. Only required properties and minimum
+// of items are generated deeper, so recursive schemas end.
+func WithMaxDepth(depth int) Option {
+	return func(g *Generator) {
+		g.maxDepth = depth
+	}
+}
+
+// WithOptionalProbability sets probability of optional property in object, 0.5 by default
+func WithOptionalProbability(probability float64) Option {
+	return func(g *Generator) {
+		g.optionalProbability = probability
+	}
+}
+
+// WithMaxItems sets maximum count of items in arrays without maxItems, 3 by default
+func WithMaxItems(count int) Option {
+	return func(g *Generator) {
+		g.maxItems = count
+	}
+}
+
+// New creates generator with seed. Generator is not safe for concurrent use.
+func New(seed int64, opts ...Option) *Generator {
+	g := &Generator{rand: rand.New(rand.NewSource(seed)), maxDepth: 5, optionalProbability: 0.5, maxItems: 3}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Generate generates instance of schema. Every next instance of generator differs.
+func (g *Generator) Generate(schema *entity.JSONSchema) (any, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := g.GenerateJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	return value, json.Unmarshal(instance, &value)
+}
+
+// GenerateJSON generates instance of schema document. Only local references are supported.
+func (g *Generator) GenerateJSON(schema []byte) ([]byte, error) {
+	var document any
+	if err := json.Unmarshal(schema, &document); err != nil {
+		return nil, err
+	}
+	// Instance is validated, because generation does not honor keywords like not and oneOf exactly.
+	// Compilation also rejects reference cycles, that generation would follow forever.
+	compiled, err := validator.CompileJSON(schema, validator.WithFormatAssertion())
+	if err != nil {
+		return nil, err
+	}
+
+	var validationErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		instance, err := (&instanceGenerator{Generator: g, root: document}).generate(document, 0)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(instance)
+		if err != nil {
+			return nil, err
+		}
+		if validationErr = compiled.ValidateBytes(data); validationErr == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %w", ErrUnsatisfiable, validationErr)
+}
+
+// PopulateExamples adds count instances of schema generated with seed to examples of schema.
+// Instances of object schema are objects.
+func PopulateExamples(schema *entity.JSONSchema, seed int64, count int) error {
+	g := New(seed)
+	for i := 0; i < count; i++ {
+		instance, err := g.Generate(schema)
+		if err != nil {
+			return err
+		}
+		example, ok := instance.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: example %v is not an object", ErrUnsatisfiable, instance)
+		}
+		schema.Examples = append(schema.Examples, &example)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package fake
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// formatGenerators generate strings of formats. Strings of unknown formats are generated like strings
+// without format.
+var formatGenerators = map[string]func(r *rand.Rand) string{
+	"date-time": func(r *rand.Rand) string { return randomTime(r).Format(time.RFC3339) },
+	"date":      func(r *rand.Rand) string { return randomTime(r).Format(time.DateOnly) },
+	"time":      func(r *rand.Rand) string { return randomTime(r).Format("15:04:05Z07:00") },
+	"email":     func(r *rand.Rand) string { return randomWord(r, 3+r.Intn(6)) + "@example.com" },
+	"hostname":  func(r *rand.Rand) string { return randomWord(r, 3+r.Intn(6)) + ".example.com" },
+	"uri":       func(r *rand.Rand) string { return "https://example.com/" + randomWord(r, 3+r.Intn(6)) },
+	"ipv4": func(r *rand.Rand) string {
+		return net.IPv4(byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256))).String()
+	},
+	"ipv6": func(r *rand.Rand) string {
+		ip := make(net.IP, net.IPv6len)
+		r.Read(ip)
+		// Address must not be IPv4-mapped to be formatted as IPv6
+		ip[0] |= 0x20
+		return ip.String()
+	},
+	"uuid": func(r *rand.Rand) string {
+		data := make([]byte, 16)
+		r.Read(data)
+		// Version 4 and variant 1 of RFC 4122
+		data[6] = data[6]&0x0f | 0x40
+		data[8] = data[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:])
+	},
+	"regex": func(r *rand.Rand) string { return "^" + randomWord(r, 3+r.Intn(6)) + "$" },
+}
+
+// randomTime returns time of 2024 year in UTC
+func randomTime(r *rand.Rand) time.Time {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start.Add(time.Duration(r.Int63n(int64(365 * 24 * time.Hour))).Truncate(time.Second))
+}
+
+// randomWord returns word of lowercase latin letters
+func randomWord(r *rand.Rand, length int) string {
+	word := make([]byte, length)
+	for i := range word {
+		word[i] = byte('a' + r.Intn(26))
+	}
+	return string(word)
+}
@@ -0,0 +1,502 @@
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxNesting limits nesting of instance, if recursive schema requires values deeper than maximum depth
+const maxNesting = 100
+
+// instanceGenerator generates instance of schema document
+type instanceGenerator struct {
+	*Generator
+	root any
+}
+
+func (g *instanceGenerator) generate(schema any, depth int) (any, error) {
+	if depth > maxNesting {
+		return nil, fmt.Errorf("%w: required values are nested deeper than %d", ErrUnsatisfiable, maxNesting)
+	}
+	keywords, err := g.keywords(schema)
+	if err != nil {
+		return nil, err
+	}
+	if keywords == nil {
+		return nil, fmt.Errorf("%w: schema false", ErrUnsatisfiable)
+	}
+
+	if value, ok := keywords["const"]; ok {
+		return value, nil
+	}
+	if values, ok := keywords["enum"].([]any); ok {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%w: empty enum", ErrUnsatisfiable)
+		}
+		return values[g.rand.Intn(len(values))], nil
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if branches, ok := keywords[keyword].([]any); ok && len(branches) > 0 {
+			branch := branches[g.rand.Intn(len(branches))]
+			rest := make(map[string]any, len(keywords))
+			for name, value := range keywords {
+				if name != keyword {
+					rest[name] = value
+				}
+			}
+			merged, err := g.merge([]any{rest, branch})
+			if err != nil {
+				return nil, err
+			}
+			return g.generate(merged, depth)
+		}
+	}
+
+	switch g.chooseType(keywords, depth) {
+	case "null":
+		return nil, nil
+	case "boolean":
+		return g.rand.Intn(2) == 1, nil
+	case "integer":
+		return g.generateNumber(keywords, true)
+	case "number":
+		return g.generateNumber(keywords, false)
+	case "array":
+		return g.generateArray(keywords, depth)
+	case "object":
+		return g.generateObject(keywords, depth)
+	}
+	return g.generateString(keywords)
+}
+
+// keywords returns keywords of schema with resolved references and merged allOf, nil for schema false
+func (g *instanceGenerator) keywords(schema any) (map[string]any, error) {
+	switch schema := schema.(type) {
+	case bool:
+		if schema {
+			return map[string]any{}, nil
+		}
+		return nil, nil
+	case map[string]any:
+		ref, hasRef := schema["$ref"].(string)
+		allOf, hasAllOf := schema["allOf"].([]any)
+		if !hasRef && !hasAllOf {
+			return schema, nil
+		}
+		rest := make(map[string]any, len(schema))
+		for keyword, value := range schema {
+			if keyword != "$ref" && keyword != "allOf" {
+				rest[keyword] = value
+			}
+		}
+		schemas := append([]any{rest}, allOf...)
+		if hasRef {
+			target, err := g.resolve(ref)
+			if err != nil {
+				return nil, err
+			}
+			schemas = append(schemas, target)
+		}
+		return g.merge(schemas)
+	}
+	return nil, fmt.Errorf("invalid schema %v", schema)
+}
+
+func (g *instanceGenerator) resolve(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok || (pointer != "" && !strings.HasPrefix(pointer, "/")) {
+		return nil, fmt.Errorf("%w: only local references are supported: %s", ErrReferenceNotFound, ref)
+	}
+	value := g.root
+	for _, token := range strings.Split(pointer, "/")[1:] {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch container := value.(type) {
+		case map[string]any:
+			value, ok = container[token]
+		case []any:
+			index, err := strconv.Atoi(token)
+			ok = err == nil && index >= 0 && index < len(container)
+			if ok {
+				value = container[index]
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrReferenceNotFound, ref)
+		}
+	}
+	return value, nil
+}
+
+// merge merges schemas, that apply together. Bounds are narrowed, properties of the same name are merged
+// by allOf, other keywords of later schemas override earlier ones.
+func (g *instanceGenerator) merge(schemas []any) (map[string]any, error) {
+	merged := make(map[string]any)
+	for _, schema := range schemas {
+		keywords, err := g.keywords(schema)
+		if err != nil {
+			return nil, err
+		}
+		if keywords == nil {
+			return nil, nil
+		}
+		for keyword, value := range keywords {
+			current, ok := merged[keyword]
+			if !ok {
+				merged[keyword] = value
+				continue
+			}
+			switch keyword {
+			case "properties":
+				properties := make(map[string]any)
+				for name, property := range current.(map[string]any) {
+					properties[name] = property
+				}
+				for name, property := range value.(map[string]any) {
+					if currentProperty, ok := properties[name]; ok {
+						property = map[string]any{"allOf": []any{currentProperty, property}}
+					}
+					properties[name] = property
+				}
+				merged[keyword] = properties
+			case "required":
+				required := slices.Clone(current.([]any))
+				for _, name := range value.([]any) {
+					if !slices.Contains(required, name) {
+						required = append(required, name)
+					}
+				}
+				merged[keyword] = required
+			case "type":
+				var intersection []any
+				for _, dataType := range types(value) {
+					for _, currentType := range types(current) {
+						// Integer is subset of number
+						common, ok := dataType, dataType == currentType
+						if (dataType == "number" && currentType == "integer") ||
+							(dataType == "integer" && currentType == "number") {
+							common, ok = "integer", true
+						}
+						if ok && !slices.Contains(intersection, any(common)) {
+							intersection = append(intersection, common)
+						}
+					}
+				}
+				merged[keyword] = intersection
+			case "minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties":
+				merged[keyword] = math.Max(toFloat(current), toFloat(value))
+			case "maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties":
+				merged[keyword] = math.Min(toFloat(current), toFloat(value))
+			default:
+				merged[keyword] = value
+			}
+		}
+	}
+	return merged, nil
+}
+
+// chooseType chooses type of instance. Type is inferred from keywords, if schema has no type.
+func (g *instanceGenerator) chooseType(keywords map[string]any, depth int) string {
+	dataTypes := types(keywords["type"])
+	if keywords["type"] == nil {
+		dataTypes = inferTypes(keywords)
+	}
+	if len(dataTypes) == 0 {
+		return ""
+	}
+	// Null ends recursion
+	if depth >= g.maxDepth && slices.Contains(dataTypes, "null") {
+		return "null"
+	}
+	return dataTypes[g.rand.Intn(len(dataTypes))]
+}
+
+func inferTypes(keywords map[string]any) []string {
+	keywordTypes := []struct {
+		dataType string
+		keywords []string
+	}{
+		{"object", []string{"properties", "required", "additionalProperties", "patternProperties", "minProperties"}},
+		{"array", []string{"items", "prefixItems", "contains", "minItems", "maxItems", "uniqueItems"}},
+		{"string", []string{"minLength", "maxLength", "pattern", "format", "contentEncoding"}},
+		{"number", []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"}},
+	}
+	for _, keywordType := range keywordTypes {
+		for _, keyword := range keywordType.keywords {
+			if _, ok := keywords[keyword]; ok {
+				return []string{keywordType.dataType}
+			}
+		}
+	}
+	return []string{"string", "integer", "boolean"}
+}
+
+func (g *instanceGenerator) generateNumber(keywords map[string]any, integer bool) (any, error) {
+	low, high := math.Inf(-1), math.Inf(1)
+	lowExclusive, highExclusive := false, false
+	if minimum, ok := keywords["minimum"].(float64); ok {
+		low = minimum
+		// Boolean exclusiveMinimum of draft 04
+		lowExclusive = keywords["exclusiveMinimum"] == true
+	}
+	if minimum, ok := keywords["exclusiveMinimum"].(float64); ok && minimum >= low {
+		low, lowExclusive = minimum, true
+	}
+	if maximum, ok := keywords["maximum"].(float64); ok {
+		high = maximum
+		highExclusive = keywords["exclusiveMaximum"] == true
+	}
+	if maximum, ok := keywords["exclusiveMaximum"].(float64); ok && maximum <= high {
+		high, highExclusive = maximum, true
+	}
+	switch {
+	case math.IsInf(low, -1) && math.IsInf(high, 1):
+		low, high = 0, 100
+	case math.IsInf(low, -1):
+		low = high - 100
+	case math.IsInf(high, 1):
+		high = low + 100
+	}
+
+	step := 1.0
+	if multipleOf, ok := keywords["multipleOf"].(float64); ok && multipleOf > 0 {
+		step = multipleOf
+	}
+	if integer || keywords["multipleOf"] != nil {
+		first, last := math.Ceil(low/step), math.Floor(high/step)
+		if lowExclusive && first*step == low {
+			first++
+		}
+		if highExclusive && last*step == high {
+			last--
+		}
+		if first > last {
+			return nil, fmt.Errorf("%w: no multiple of %v between %v and %v", ErrUnsatisfiable, step, low, high)
+		}
+		// Count of multiples can be greater than int64 holds, then multiple is chosen in float64
+		span := last - first
+		var offset float64
+		if span < 1<<62 {
+			offset = float64(g.rand.Int63n(int64(span) + 1))
+		} else {
+			offset = math.Min(math.Floor(g.rand.Float64()*(span+1)), span)
+		}
+		value := (first + offset) * step
+		if integer {
+			// float64 of math.MaxInt64 is 2^63, that int64 can not hold
+			if value >= math.MaxInt64 {
+				return int64(math.MaxInt64), nil
+			}
+			return int64(value), nil
+		}
+		return value, nil
+	}
+
+	value := low + g.rand.Float64()*(high-low)
+	if rounded := math.Round(value*100) / 100; rounded > low && rounded < high {
+		value = rounded
+	}
+	if lowExclusive && value == low {
+		value = (low + high) / 2
+	}
+	return value, nil
+}
+
+func (g *instanceGenerator) generateString(keywords map[string]any) (any, error) {
+	minLength := int(toFloat(keywords["minLength"]))
+	maxLength := math.MaxInt
+	if length, ok := keywords["maxLength"].(float64); ok {
+		maxLength = int(length)
+	}
+	if minLength > maxLength {
+		return nil, fmt.Errorf("%w: minLength %d is greater than maxLength %d", ErrUnsatisfiable, minLength, maxLength)
+	}
+	if keywords["contentEncoding"] == "base64" {
+		data := make([]byte, 1+g.rand.Intn(12))
+		g.rand.Read(data)
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+
+	format, _ := keywords["format"].(string)
+	if generateFormat, ok := formatGenerators[format]; ok {
+		return generateFormat(g.rand), nil
+	}
+	if pattern, ok := keywords["pattern"].(string); ok {
+		return generatePattern(g.rand, pattern, minLength, maxLength)
+	}
+
+	low := max(minLength, min(1, maxLength))
+	high := min(maxLength, low+9)
+	return randomWord(g.rand, low+g.rand.Intn(high-low+1)), nil
+}
+
+func (g *instanceGenerator) generateArray(keywords map[string]any, depth int) (any, error) {
+	prefixItems, _ := keywords["prefixItems"].([]any)
+	items := keywords["items"]
+	// Items of draft 04-07 can be array of schemas like prefixItems
+	if itemsArray, ok := items.([]any); ok {
+		prefixItems, items = itemsArray, keywords["additionalItems"]
+	}
+	if items == nil {
+		items = true
+	}
+
+	low := int(toFloat(keywords["minItems"]))
+	high := low + g.maxItems
+	if maxItems, ok := keywords["maxItems"].(float64); ok {
+		high = min(high, int(maxItems))
+	}
+	if items == false {
+		high = min(high, len(prefixItems))
+	}
+	if depth >= g.maxDepth {
+		high = min(high, low)
+	}
+	if low > high {
+		return nil, fmt.Errorf("%w: array needs %d items, but allows %d", ErrUnsatisfiable, low, high)
+	}
+	count := low + g.rand.Intn(high-low+1)
+
+	contains, hasContains := keywords["contains"]
+	minContains := 1
+	if value, ok := keywords["minContains"].(float64); ok {
+		minContains = int(value)
+	}
+	if hasContains {
+		count = max(count, len(prefixItems)+minContains)
+	}
+
+	result := make([]any, 0, count)
+	unique := keywords["uniqueItems"] == true
+	seen := make(map[string]bool)
+	for i := 0; i < count; i++ {
+		itemSchema := items
+		switch {
+		case i < len(prefixItems):
+			itemSchema = prefixItems[i]
+		case hasContains && i < len(prefixItems)+minContains:
+			itemSchema = contains
+		}
+		var item any
+		for attempt := 0; ; attempt++ {
+			var err error
+			if item, err = g.generate(itemSchema, depth+1); err != nil {
+				return nil, err
+			}
+			data, _ := json.Marshal(item)
+			if !unique || !seen[string(data)] {
+				seen[string(data)] = true
+				break
+			}
+			if attempt == maxAttempts {
+				return nil, fmt.Errorf("%w: unique items", ErrUnsatisfiable)
+			}
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+func (g *instanceGenerator) generateObject(keywords map[string]any, depth int) (any, error) {
+	properties, _ := keywords["properties"].(map[string]any)
+	required, _ := keywords["required"].([]any)
+	dependentRequired, _ := keywords["dependentRequired"].(map[string]any)
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	included := make(map[string]bool)
+	for _, name := range required {
+		if name, ok := name.(string); ok {
+			included[name] = true
+		}
+	}
+	for _, name := range names {
+		if depth < g.maxDepth && g.rand.Float64() < g.optionalProbability {
+			included[name] = true
+		}
+	}
+	if minProperties, ok := keywords["minProperties"].(float64); ok {
+		for _, name := range names {
+			if len(included) >= int(minProperties) {
+				break
+			}
+			included[name] = true
+		}
+		for i := 1; len(included) < int(minProperties) && keywords["additionalProperties"] != false; i++ {
+			included["additional"+strconv.Itoa(i)] = true
+		}
+	}
+	if maxProperties, ok := keywords["maxProperties"].(float64); ok {
+		for i := len(names) - 1; i >= 0 && len(included) > int(maxProperties); i-- {
+			if !slices.Contains(required, any(names[i])) {
+				delete(included, names[i])
+			}
+		}
+	}
+	// Dependent properties are required, if property is present
+	for changed := true; changed; {
+		changed = false
+		for name := range included {
+			dependents, _ := dependentRequired[name].([]any)
+			for _, dependent := range dependents {
+				if dependent, ok := dependent.(string); ok && !included[dependent] {
+					included[dependent], changed = true, true
+				}
+			}
+		}
+	}
+
+	includedNames := make([]string, 0, len(included))
+	for name := range included {
+		includedNames = append(includedNames, name)
+	}
+	sort.Strings(includedNames)
+	result := make(map[string]any, len(included))
+	for _, name := range includedNames {
+		propertySchema, ok := properties[name]
+		if !ok {
+			if propertySchema, ok = keywords["additionalProperties"]; !ok {
+				propertySchema = true
+			}
+		}
+		value, err := g.generate(propertySchema, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", name, err)
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// types returns types of type keyword
+func types(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		result := make([]string, 0, len(value))
+		for _, dataType := range value {
+			if dataType, ok := dataType.(string); ok {
+				result = append(result, dataType)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func toFloat(value any) float64 {
+	number, _ := value.(float64)
+	return number
+}
@@ -0,0 +1,92 @@
+package fake
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxRepeat limits repetitions of unbounded quantifiers like * and +
+const maxRepeat = 5
+
+// generatePattern generates string, that matches pattern and has length between minLength and maxLength
+func generatePattern(r *rand.Rand, pattern string, minLength, maxLength int) (string, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("pattern %s: %w", pattern, err)
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("pattern %s: %w", pattern, err)
+	}
+	parsed = parsed.Simplify()
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var builder strings.Builder
+		generateRegexp(r, parsed, &builder)
+		value := builder.String()
+		length := utf8.RuneCountInString(value)
+		if length >= minLength && length <= maxLength && compiled.MatchString(value) {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("%w: string of pattern %s with length from %d to %d", ErrUnsatisfiable, pattern,
+		minLength, maxLength)
+}
+
+// generateRegexp writes string matching regular expression
+func generateRegexp(r *rand.Rand, re *syntax.Regexp, builder *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, literal := range re.Rune {
+			builder.WriteRune(literal)
+		}
+	case syntax.OpCharClass:
+		builder.WriteRune(randomRune(r, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		builder.WriteByte(byte('a' + r.Intn(26)))
+	case syntax.OpCapture:
+		generateRegexp(r, re.Sub[0], builder)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			generateRegexp(r, sub, builder)
+		}
+	case syntax.OpAlternate:
+		generateRegexp(r, re.Sub[r.Intn(len(re.Sub))], builder)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		low, high := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			low, high = 0, maxRepeat
+		case syntax.OpPlus:
+			low, high = 1, maxRepeat
+		case syntax.OpQuest:
+			low, high = 0, 1
+		}
+		if high < 0 {
+			high = low + maxRepeat
+		}
+		for count := low + r.Intn(high-low+1); count > 0; count-- {
+			generateRegexp(r, re.Sub[0], builder)
+		}
+	}
+	// Anchors, word boundaries and empty matches write nothing
+}
+
+// randomRune returns rune of character class, that is pairs of rune ranges. Printable ASCII runes are preferred.
+func randomRune(r *rand.Rand, ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for char := max(ranges[i], ' '); char <= min(ranges[i+1], '~'); char++ {
+			printable = append(printable, char)
+		}
+	}
+	if len(printable) > 0 {
+		return printable[r.Intn(len(printable))]
+	}
+	low, high := ranges[0], ranges[1]
+	return low + rune(r.Int63n(int64(high-low)+1))
+}
@@ -0,0 +1,156 @@
+package jsonschematest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var ErrGoldenMismatch = errors.New("output differs from golden file")
+
+// update rewrites golden files instead of comparing, e.g. go test ./... -jsonschematest.update. Flag is namespaced,
+// so it does not conflict with -update flag of test packages, which is not honored
+var update = flag.Bool("jsonschematest.update", false, "rewrite golden files of jsonschematest")
+
+// AssertSchemaGolden fails test, if schema of obj type generated by ReflectParser differs from golden file,
+// see CheckGolden
+func AssertSchemaGolden(t testing.TB, obj any, filename string) {
+	t.Helper()
+	generator, err := jsonschema.FromTypeToJsonSchema(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := generator.ToJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertGolden(t, actual, filename)
+}
+
+// AssertGolden fails test, if JSON document differs from golden file, see CheckGolden
+func AssertGolden(t testing.TB, actual []byte, filename string) {
+	t.Helper()
+	if err := CheckGolden(actual, filename); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CheckGolden compares JSON document with golden file. Order of keys is a part of expected output, so documents
+// are compared without whitespaces only. Error lists different values by JSON Pointer. Golden file is rewritten
+// with -jsonschematest.update flag.
+func CheckGolden(actual []byte, filename string) error {
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(actual, []byte("\n")) {
+			actual = append(actual, '\n')
+		}
+		return os.WriteFile(filename, actual, 0o644)
+	}
+
+	expectedJSON, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("%w, run test with -jsonschematest.update to create it", err)
+	}
+	var expectedCompact, actualCompact bytes.Buffer
+	if err = json.Compact(&expectedCompact, expectedJSON); err != nil {
+		return fmt.Errorf("golden file %s: %w", filename, err)
+	}
+	if err = json.Compact(&actualCompact, actual); err != nil {
+		return err
+	}
+	if bytes.Equal(expectedCompact.Bytes(), actualCompact.Bytes()) {
+		return nil
+	}
+
+	var expected, value any
+	if err = json.Unmarshal(expectedJSON, &expected); err != nil {
+		return fmt.Errorf("golden file %s: %w", filename, err)
+	}
+	if err = json.Unmarshal(actual, &value); err != nil {
+		return err
+	}
+	differences := diffValues("#", expected, value)
+	if len(differences) == 0 {
+		differences = []string{"order of keys differs"}
+	}
+	return fmt.Errorf("%w %s, run test with -jsonschematest.update to rewrite it:\n%s", ErrGoldenMismatch, filename,
+		strings.Join(differences, "\n"))
+}
+
+// diffValues returns differences of decoded JSON values as lines with JSON Pointer of value
+func diffValues(pointer string, expected, actual any) []string {
+	switch expected := expected.(type) {
+	case map[string]any:
+		actual, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+		names := make([]string, 0, len(expected)+len(actual))
+		for name := range expected {
+			names = append(names, name)
+		}
+		for name := range actual {
+			if _, ok := expected[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		var differences []string
+		for _, name := range names {
+			namePointer := pointer + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+			expectedValue, hasExpected := expected[name]
+			actualValue, hasActual := actual[name]
+			switch {
+			case !hasActual:
+				differences = append(differences, fmt.Sprintf("%s: missing, expected %s", namePointer,
+					encode(expectedValue)))
+			case !hasExpected:
+				differences = append(differences, fmt.Sprintf("%s: unexpected %s", namePointer, encode(actualValue)))
+			default:
+				differences = append(differences, diffValues(namePointer, expectedValue, actualValue)...)
+			}
+		}
+		return differences
+	case []any:
+		actual, ok := actual.([]any)
+		if !ok {
+			break
+		}
+		var differences []string
+		for i := 0; i < max(len(expected), len(actual)); i++ {
+			itemPointer := pointer + "/" + strconv.Itoa(i)
+			switch {
+			case i >= len(actual):
+				differences = append(differences, fmt.Sprintf("%s: missing, expected %s", itemPointer,
+					encode(expected[i])))
+			case i >= len(expected):
+				differences = append(differences, fmt.Sprintf("%s: unexpected %s", itemPointer, encode(actual[i])))
+			default:
+				differences = append(differences, diffValues(itemPointer, expected[i], actual[i])...)
+			}
+		}
+		return differences
+	}
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: expected %s, actual %s", pointer, encode(expected), encode(actual))}
+}
+
+func encode(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,111 @@
+// Package jsonschematest provides helpers to test Go types and their generated JSON Schema: round trip of
+// instances through encoding/json and comparison with golden files.
+package jsonschematest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/fake"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"reflect"
+	"testing"
+)
+
+var ErrRoundTrip = errors.New("round trip failed")
+
+// roundTrip configures CheckRoundTrip
+type roundTrip struct {
+	seed       int64
+	count      int
+	fakeOption []fake.Option
+}
+
+// Option configures CheckRoundTrip and AssertRoundTrip
+type Option func(r *roundTrip)
+
+// WithSeed sets seed of generated instances, 1 by default
+func WithSeed(seed int64) Option {
+	return func(r *roundTrip) {
+		r.seed = seed
+	}
+}
+
+// WithCount sets count of generated instances, 100 by default
+func WithCount(count int) Option {
+	return func(r *roundTrip) {
+		r.count = count
+	}
+}
+
+// WithFakeOptions configures generator of instances
+func WithFakeOptions(opts ...fake.Option) Option {
+	return func(r *roundTrip) {
+		r.fakeOption = append(r.fakeOption, opts...)
+	}
+}
+
+// AssertRoundTrip fails test, if instances of schema of obj type do not survive round trip, see CheckRoundTrip
+func AssertRoundTrip(t testing.TB, obj any, opts ...Option) {
+	t.Helper()
+	if err := CheckRoundTrip(obj, opts...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CheckRoundTrip generates schema of obj type by ReflectParser and random instances of the schema. Every instance
+// is unmarshalled into new value of the type, marshalled back and validated against the schema again. Error
+// shows drift between schema and encoding/json, e.g. property unknown to the type, wrong field name or
+// custom marshalling.
+func CheckRoundTrip(obj any, opts ...Option) error {
+	r := &roundTrip{seed: 1, count: 100}
+	for _, opt := range opts {
+		opt(r)
+	}
+	typ := reflect.TypeOf(obj)
+	if typ == nil {
+		return jsonschema.ErrNilValue
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	generator, err := jsonschema.FromTypeToJsonSchema(reflect.Zero(typ).Interface())
+	if err != nil {
+		return fmt.Errorf("generate schema for %s: %w", typ, err)
+	}
+	schema, err := generator.ToJson()
+	if err != nil {
+		return err
+	}
+	compiled, err := validator.CompileJSON(schema, validator.WithFormatAssertion())
+	if err != nil {
+		return fmt.Errorf("compile schema for %s: %w", typ, err)
+	}
+
+	instances := fake.New(r.seed, r.fakeOption...)
+	for i := 0; i < r.count; i++ {
+		instance, err := instances.GenerateJSON(schema)
+		if err != nil {
+			return fmt.Errorf("generate instance of %s: %w", typ, err)
+		}
+
+		value := reflect.New(typ)
+		decoder := json.NewDecoder(bytes.NewReader(instance))
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(value.Interface()); err != nil {
+			return fmt.Errorf("%w: unmarshal instance %s into %s: %w", ErrRoundTrip, instance, typ, err)
+		}
+		output, err := json.Marshal(value.Interface())
+		if err != nil {
+			return fmt.Errorf("%w: marshal %s of instance %s: %w", ErrRoundTrip, typ, instance, err)
+		}
+		if err = compiled.ValidateBytes(output); err != nil {
+			return fmt.Errorf("%w: %s of instance %s is marshalled to %s: %w", ErrRoundTrip, typ, instance, output,
+				err)
+		}
+	}
+	return nil
+}
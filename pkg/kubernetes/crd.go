@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"strings"
+)
+
+// Scope is scope of custom resource
+type Scope string
+
+const (
+	ScopeNamespaced Scope = "Namespaced"
+	ScopeCluster    Scope = "Cluster"
+)
+
+var ErrGroupNotFound = errors.New("group of custom resource is required")
+var ErrKindNotFound = errors.New("kind of custom resource is required")
+
+// Config describes custom resource. Only Group and Kind are required, names are derived from Kind.
+type Config struct {
+	Group string
+	Kind  string
+	// Version is name of API version, v1 by default
+	Version string
+	// Plural is lowercase plural name used in URL, lowercase Kind with "s" by default
+	Plural string
+	// Singular is lowercase singular name, lowercase Kind by default
+	Singular   string
+	ShortNames []string
+	// Scope is ScopeNamespaced by default
+	Scope Scope
+}
+
+// CustomResourceDefinition is apiextensions.k8s.io/v1 CustomResourceDefinition with single version
+type CustomResourceDefinition struct {
+	APIVersion string                       `json:"apiVersion"`
+	Kind       string                       `json:"kind"`
+	Metadata   ObjectMeta                   `json:"metadata"`
+	Spec       CustomResourceDefinitionSpec `json:"spec"`
+}
+
+type ObjectMeta struct {
+	Name string `json:"name"`
+}
+
+type CustomResourceDefinitionSpec struct {
+	Group    string                            `json:"group"`
+	Names    CustomResourceDefinitionNames     `json:"names"`
+	Scope    Scope                             `json:"scope"`
+	Versions []CustomResourceDefinitionVersion `json:"versions"`
+}
+
+type CustomResourceDefinitionNames struct {
+	Kind       string   `json:"kind"`
+	ListKind   string   `json:"listKind"`
+	Plural     string   `json:"plural"`
+	Singular   string   `json:"singular"`
+	ShortNames []string `json:"shortNames,omitempty"`
+}
+
+type CustomResourceDefinitionVersion struct {
+	Name    string                   `json:"name"`
+	Served  bool                     `json:"served"`
+	Storage bool                     `json:"storage"`
+	Schema  CustomResourceValidation `json:"schema"`
+}
+
+type CustomResourceValidation struct {
+	OpenAPIV3Schema *entity.OrderedMap[any] `json:"openAPIV3Schema"`
+}
+
+// NewCustomResourceDefinition creates definition of custom resource with structural schema,
+// see StructuralSchema. The only version is served and stored.
+func NewCustomResourceDefinition(config Config, schema *entity.OrderedMap[any]) (*CustomResourceDefinition, error) {
+	if config.Group == "" {
+		return nil, ErrGroupNotFound
+	}
+	if config.Kind == "" {
+		return nil, ErrKindNotFound
+	}
+	if config.Version == "" {
+		config.Version = "v1"
+	}
+	if config.Singular == "" {
+		config.Singular = strings.ToLower(config.Kind)
+	}
+	if config.Plural == "" {
+		config.Plural = config.Singular + "s"
+	}
+	if config.Scope == "" {
+		config.Scope = ScopeNamespaced
+	}
+
+	return &CustomResourceDefinition{
+		APIVersion: "apiextensions.k8s.io/v1",
+		Kind:       "CustomResourceDefinition",
+		Metadata:   ObjectMeta{Name: fmt.Sprintf("%s.%s", config.Plural, config.Group)},
+		Spec: CustomResourceDefinitionSpec{
+			Group: config.Group,
+			Names: CustomResourceDefinitionNames{
+				Kind:       config.Kind,
+				ListKind:   config.Kind + "List",
+				Plural:     config.Plural,
+				Singular:   config.Singular,
+				ShortNames: config.ShortNames,
+			},
+			Scope: config.Scope,
+			Versions: []CustomResourceDefinitionVersion{{
+				Name:    config.Version,
+				Served:  true,
+				Storage: true,
+				Schema:  CustomResourceValidation{OpenAPIV3Schema: schema},
+			}},
+		},
+	}, nil
+}
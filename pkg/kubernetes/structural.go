@@ -0,0 +1,216 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/converter"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/openapi"
+	"github.com/paulrozhkin/jsonschema/pkg/transform"
+	"strings"
+)
+
+// TagName is name of struct tag with Kubernetes markers, e.g. `kubernetes:"listType=map,listMapKey=name"`
+const TagName = "kubernetes"
+
+var ErrRecursiveType = errors.New("recursive type can not be inlined into structural schema")
+
+// intOrStringType is ID of k8s.io/apimachinery IntOrString, that is integer or string in JSON
+const intOrStringType = "k8s.io/apimachinery/pkg/util/intstr#IntOrString"
+
+// StructuralSchema converts root type of bundle to structural schema of custom resource:
+// OpenAPI 3.0 schema without references, where every value has type. Values of any type preserve
+// unknown fields. Markers of fields are read from TagName tag:
+//
+//   - listType=atomic|set|map and listMapKey=<property> for slices
+//   - mapType=atomic|granular for maps and structs
+//   - preserveUnknownFields, intOrString and embeddedResource
+func StructuralSchema(bundle *entity.JSONSchema, metadata *entity.JsonSchemaMetadata) (*entity.OrderedMap[any], error) {
+	if metadata.Root == nil {
+		return nil, converter.ErrRootNotFound
+	}
+	inlined, err := inlineRoot(bundle, metadata.Root.TypeName)
+	if err != nil {
+		return nil, err
+	}
+	components, err := openapi.FromBundle(entity.NewJSONEmptySchema().AddDefinition(metadata.Root.TypeName, inlined),
+		openapi.Version30)
+	if err != nil {
+		return nil, err
+	}
+
+	root, _ := components.Schemas.Get(metadata.Root.TypeName)
+	schema := root.(*entity.OrderedMap[any])
+	if err = markFields(schema, metadata.Root); err != nil {
+		return nil, err
+	}
+	addResourceProperties(schema)
+	preserveUnknownFields(schema)
+	return schema, nil
+}
+
+// inlineRoot returns copy of definition of root type with inlined references, see transform.InlineRefs
+func inlineRoot(bundle *entity.JSONSchema, rootName string) (*entity.JSONSchema, error) {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	var bundleCopy entity.JSONSchema
+	if err = json.Unmarshal(data, &bundleCopy); err != nil {
+		return nil, err
+	}
+	def, ok := bundleCopy.Defs.Get(rootName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", transform.ErrDefinitionNotFound, rootName)
+	}
+	root, ok := def.(*entity.ObjectSchema)
+	if !ok {
+		return nil, fmt.Errorf("definition %s is not an object schema", rootName)
+	}
+
+	schema := &entity.JSONSchema{ObjectSchema: *root, Defs: bundleCopy.Defs}
+	if err = transform.InlineRefs(schema); err != nil {
+		return nil, err
+	}
+	// InlineRefs keeps only definitions of recursive types
+	if schema.Defs.Len() > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRecursiveType, strings.Join(schema.Defs.Keys(), ", "))
+	}
+	return schema, nil
+}
+
+// markFields applies markers of fields of struct to its inlined schema, including fields of nested structs
+func markFields(schema *entity.OrderedMap[any], dataTypeMetadata *entity.DataTypeMetadata) error {
+	if dataTypeMetadata.Ref != nil {
+		dataTypeMetadata = dataTypeMetadata.Ref
+	}
+	properties, _ := get(schema, "properties").(*entity.OrderedMap[any])
+	for _, node := range dataTypeMetadata.Nodes {
+		property, ok := get(properties, converter.FieldName(node)).(*entity.OrderedMap[any])
+		if !ok {
+			continue
+		}
+		if err := markNestedFields(property, node); err != nil {
+			return err
+		}
+		if err := applyMarkers(property, node); err != nil {
+			return fmt.Errorf("field %s of %s: %w", node.FieldName, dataTypeMetadata.TypeName, err)
+		}
+	}
+	return nil
+}
+
+// markNestedFields applies markers of struct, that is value of field or element of collection field
+func markNestedFields(property *entity.OrderedMap[any], node *entity.DataTypeMetadata) error {
+	if node.Ref != nil {
+		return markFields(property, node.Ref)
+	}
+	if len(node.Nodes) != 1 || node.Nodes[0].Ref == nil {
+		return nil
+	}
+	for _, keyword := range []string{"items", "additionalProperties"} {
+		if elem, ok := get(property, keyword).(*entity.OrderedMap[any]); ok {
+			return markFields(elem, node.Nodes[0].Ref)
+		}
+	}
+	return nil
+}
+
+// applyMarkers adds Kubernetes extensions to schema of field
+func applyMarkers(property *entity.OrderedMap[any], node *entity.DataTypeMetadata) error {
+	if node.Ref != nil && node.Ref.ID() == intOrStringType {
+		setIntOrString(property)
+	}
+
+	for _, tag := range node.Tags[TagName] {
+		name, value, _ := strings.Cut(tag, "=")
+		switch name {
+		case "listType":
+			if get(property, "type") != "array" {
+				return fmt.Errorf("marker %s is allowed only for slices", tag)
+			}
+			if value != "atomic" && value != "set" && value != "map" {
+				return fmt.Errorf("unknown list type %s", value)
+			}
+			property.Set("x-kubernetes-list-type", value)
+		case "listMapKey":
+			keys, _ := get(property, "x-kubernetes-list-map-keys").([]any)
+			property.Set("x-kubernetes-list-map-keys", append(keys, value))
+		case "mapType":
+			if value != "atomic" && value != "granular" {
+				return fmt.Errorf("unknown map type %s", value)
+			}
+			property.Set("x-kubernetes-map-type", value)
+		case "preserveUnknownFields":
+			property.Set("x-kubernetes-preserve-unknown-fields", true)
+		case "intOrString":
+			setIntOrString(property)
+		case "embeddedResource":
+			property.Set("x-kubernetes-embedded-resource", true)
+		default:
+			return fmt.Errorf("unknown marker %s", tag)
+		}
+	}
+
+	_, hasKeys := property.Get("x-kubernetes-list-map-keys")
+	if hasKeys != (get(property, "x-kubernetes-list-type") == "map") {
+		return errors.New("listMapKey must be used together with listType=map")
+	}
+	return nil
+}
+
+// setIntOrString replaces schema by schema of value, that is integer or string
+func setIntOrString(property *entity.OrderedMap[any]) {
+	for _, keyword := range property.Keys() {
+		if keyword != "description" {
+			property.Delete(keyword)
+		}
+	}
+	property.Set("x-kubernetes-int-or-string", true)
+	property.Set("anyOf", []any{entity.NewOrderedMap[any]().Set("type", "integer"),
+		entity.NewOrderedMap[any]().Set("type", "string")})
+}
+
+// addResourceProperties adds properties, that every resource has, if the root type does not declare them.
+// Added properties precede properties of the root type.
+func addResourceProperties(schema *entity.OrderedMap[any]) {
+	properties, _ := get(schema, "properties").(*entity.OrderedMap[any])
+	resourceProperties := entity.NewOrderedMap[any]()
+	for _, property := range [][2]string{{"apiVersion", "string"}, {"kind", "string"}, {"metadata", "object"}} {
+		if _, ok := properties.Get(property[0]); !ok {
+			resourceProperties.Set(property[0], entity.NewOrderedMap[any]().Set("type", property[1]))
+		}
+	}
+	for _, name := range properties.Keys() {
+		resourceProperties.Set(name, get(properties, name))
+	}
+	schema.Set("properties", resourceProperties)
+}
+
+// preserveUnknownFields marks schemas of any value, so Kubernetes keeps their content. Structural schema
+// requires type of every value except such values.
+func preserveUnknownFields(schema *entity.OrderedMap[any]) {
+	_, hasType := schema.Get("type")
+	_, isIntOrString := schema.Get("x-kubernetes-int-or-string")
+	if !hasType && !isIntOrString {
+		schema.Set("x-kubernetes-preserve-unknown-fields", true)
+	}
+	properties, _ := get(schema, "properties").(*entity.OrderedMap[any])
+	for _, name := range properties.Keys() {
+		if property, ok := get(properties, name).(*entity.OrderedMap[any]); ok {
+			preserveUnknownFields(property)
+		}
+	}
+	for _, keyword := range []string{"items", "additionalProperties"} {
+		if subschema, ok := get(schema, keyword).(*entity.OrderedMap[any]); ok {
+			preserveUnknownFields(subschema)
+		}
+	}
+}
+
+// get returns value of keyword or nil
+func get(obj *entity.OrderedMap[any], keyword string) any {
+	value, _ := obj.Get(keyword)
+	return value
+}
@@ -0,0 +1,204 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"math/big"
+	"strings"
+)
+
+// Version is OpenAPI version of generated components
+type Version string
+
+const (
+	// Version30 uses OpenAPI 3.0 schema dialect: nullable, boolean exclusiveMinimum and exclusiveMaximum
+	Version30 Version = "3.0"
+	// Version31 uses JSON Schema 2020-12
+	Version31 Version = "3.1"
+)
+
+// SchemasRef is prefix of references to component schemas
+const SchemasRef = "#/components/schemas/"
+
+var ErrUnknownVersion = errors.New("unknown OpenAPI version")
+
+// Components is components object of OpenAPI document with schemas only
+type Components struct {
+	// Schemas are ordered like definitions of bundle, keywords of every schema keep their order too
+	Schemas *entity.OrderedMap[any] `json:"schemas"`
+}
+
+// unsupported30 are keywords of JSON Schema, that OpenAPI 3.0 schema dialect does not support
+var unsupported30 = []string{"$anchor", "$dynamicRef", "$dynamicAnchor", "$vocabulary", "if", "then", "else",
+	"dependentRequired", "dependentSchemas", "dependencies", "prefixItems", "contains", "minContains",
+	"maxContains", "propertyNames", "patternProperties", "unevaluatedItems", "unevaluatedProperties",
+	"contentSchema"}
+
+// FromBundle converts every definition of bundle schema to component schema.
+// References to definitions are replaced by references to components.
+func FromBundle(bundle *entity.JSONSchema, version Version) (*Components, error) {
+	if version != Version30 && version != Version31 {
+		return nil, fmt.Errorf("%w %s", ErrUnknownVersion, version)
+	}
+	data, err := json.Marshal(bundle.Defs)
+	if err != nil {
+		return nil, err
+	}
+	// Numbers are kept as is, e.g. integer maximum is not turned into float
+	decoded, err := entity.UnmarshalOrderedJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	schemas, _ := decoded.(*entity.OrderedMap[any])
+	if schemas == nil {
+		schemas = entity.NewOrderedMap[any]()
+	}
+
+	for _, name := range schemas.Keys() {
+		schema, _ := schemas.Get(name)
+		err = walkSchema(schema, "/"+name, func(obj *entity.OrderedMap[any], location string) error {
+			if ref, ok := get(obj, "$ref").(string); ok {
+				obj.Set("$ref", componentRef(ref))
+			}
+			// Components are parts of OpenAPI document, so they have no own dialect and base URI
+			obj.Delete("$schema")
+			obj.Delete("$id")
+			if version == Version30 {
+				return toOpenAPI30(obj, location)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Components{Schemas: schemas}, nil
+}
+
+// componentRef replaces reference to definition by reference to component
+func componentRef(ref string) string {
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			return SchemasRef + name
+		}
+	}
+	return ref
+}
+
+// toOpenAPI30 rewrites keywords of schema to OpenAPI 3.0 dialect
+func toOpenAPI30(obj *entity.OrderedMap[any], location string) error {
+	for _, keyword := range unsupported30 {
+		if _, ok := obj.Get(keyword); ok {
+			return fmt.Errorf("keyword %s is not supported by OpenAPI 3.0 at %s", keyword, location)
+		}
+	}
+	obj.Delete("$comment")
+
+	// Null type is replaced by nullable
+	if types, ok := get(obj, "type").([]any); ok {
+		var notNull []any
+		for _, typ := range types {
+			if typ != "null" {
+				notNull = append(notNull, typ)
+			}
+		}
+		if len(notNull) < len(types) {
+			obj.Set("nullable", true)
+		}
+		switch len(notNull) {
+		case 0:
+			obj.Delete("type")
+		case 1:
+			obj.Set("type", notNull[0])
+		default:
+			return fmt.Errorf("several types are not supported by OpenAPI 3.0 at %s", location)
+		}
+	} else if get(obj, "type") == "null" {
+		obj.Delete("type")
+		obj.Set("nullable", true)
+	}
+
+	// Numeric exclusive bounds are boolean modifiers of minimum and maximum. If schema has both bounds,
+	// the tighter one is kept.
+	for _, bounds := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		exclusive, bound := bounds[0], bounds[1]
+		value, ok := get(obj, exclusive).(json.Number)
+		if !ok {
+			continue
+		}
+		tighter, err := isTighterBound(value, get(obj, bound), bound == "minimum")
+		if err != nil {
+			return fmt.Errorf("%s at %s: %w", exclusive, location, err)
+		}
+		if tighter {
+			obj.Set(bound, value)
+			obj.Set(exclusive, true)
+		} else {
+			obj.Delete(exclusive)
+		}
+	}
+
+	// Base64 content is string of byte format
+	if get(obj, "contentEncoding") == "base64" {
+		obj.Delete("contentEncoding")
+		if _, ok := obj.Get("format"); !ok {
+			obj.Set("format", "byte")
+		}
+	}
+
+	if value, ok := obj.Get("const"); ok {
+		obj.Delete("const")
+		obj.Set("enum", []any{value})
+	}
+	if examples, ok := get(obj, "examples").([]any); ok {
+		obj.Delete("examples")
+		if len(examples) > 0 {
+			obj.Set("example", examples[0])
+		}
+	}
+	return nil
+}
+
+// isTighterBound reports whether exclusive bound excludes the same or more values than inclusive bound,
+// that can be absent. Both bounds are lower bounds, if isMinimum is set.
+func isTighterBound(exclusive json.Number, inclusive any, isMinimum bool) (bool, error) {
+	inclusiveNumber, ok := inclusive.(json.Number)
+	if !ok {
+		return true, nil
+	}
+	exclusiveValue, ok := new(big.Rat).SetString(exclusive.String())
+	if !ok {
+		return false, fmt.Errorf("invalid number %s", exclusive)
+	}
+	inclusiveValue, ok := new(big.Rat).SetString(inclusiveNumber.String())
+	if !ok {
+		return false, fmt.Errorf("invalid number %s", inclusiveNumber)
+	}
+	if isMinimum {
+		return exclusiveValue.Cmp(inclusiveValue) >= 0, nil
+	}
+	return exclusiveValue.Cmp(inclusiveValue) <= 0, nil
+}
+
+// walkSchema calls fn for schema and all its subschemas, see entity.EachSubschema. Location is JSON Pointer
+// of schema in components.
+func walkSchema(raw any, location string, fn func(obj *entity.OrderedMap[any], location string) error) error {
+	obj, ok := raw.(*entity.OrderedMap[any])
+	if !ok {
+		return nil
+	}
+	if err := fn(obj, location); err != nil {
+		return err
+	}
+	return entity.EachSubschema(obj, func(subschema any, pointer string) error {
+		return walkSchema(subschema, location+pointer, fn)
+	})
+}
+
+// get returns value of keyword or nil
+func get(obj *entity.OrderedMap[any], keyword string) any {
+	value, _ := obj.Get(keyword)
+	return value
+}
@@ -2,7 +2,6 @@ package parser
 
 import (
 	"errors"
-	"flag"
 	"fmt"
 	"github.com/paulrozhkin/jsonschema/pkg/entity"
 	"go/types"
@@ -14,30 +13,76 @@ import (
 )
 
 type AstParser struct {
-	typeName    string
-	packageName string
+	typeNames    []string
+	packageNames []string
+	filter       *TypeFilter
+	config       loadConfig
+	sources      map[string][]byte
 }
 
-func NewAstParser(typeName, packageName string) *AstParser {
+func NewAstParser(typeName, packageName string, opts ...AstParserOption) *AstParser {
+	return NewAstParserForTypes([]string{typeName}, packageName, opts...)
+}
+
+// NewAstParserForTypes creates parser for several root types of one package. The package is loaded once
+// and all root types share type definitions. If typeNames is empty, all exported structs of the package
+// are parsed.
+func NewAstParserForTypes(typeNames []string, packageName string, opts ...AstParserOption) *AstParser {
 	return &AstParser{
-		typeName:    typeName,
-		packageName: packageName,
+		typeNames:    typeNames,
+		packageNames: []string{packageName},
+		config:       newLoadConfig(opts),
+	}
+}
+
+// NewAstPatternParser creates parser for all exported structs of packages matched by patterns
+// (e.g. ./api/... or github.com/x/y/...), that match filter. Nil filter selects all exported structs.
+func NewAstPatternParser(patterns []string, filter *TypeFilter, opts ...AstParserOption) *AstParser {
+	return &AstParser{
+		packageNames: patterns,
+		filter:       filter,
+		config:       newLoadConfig(opts),
+	}
+}
+
+// NewAstSourceParser creates parser for Go sources kept in memory (filename to content). All sources must
+// belong to one package, that gets packagePath as import path. Sources are type checked directly without go
+// command, so they can import only standard library packages. If typeNames is empty, all exported structs are
+// parsed.
+func NewAstSourceParser(typeNames []string, packagePath string, sources map[string][]byte) *AstParser {
+	return &AstParser{
+		typeNames:    typeNames,
+		packageNames: []string{packagePath},
+		sources:      sources,
 	}
 }
 
 func (p *AstParser) Parse() (*entity.JsonSchemaMetadata, error) {
-	if p.packageName == "." {
-		dir, err := os.Getwd()
+	if p.sources != nil {
+		pkg, err := checkSources(p.packageNames[0], p.sources)
+		if err != nil {
+			return nil, fmt.Errorf("check sources: %w", err)
+		}
+		return extractMetadataFromPackages(p.config, []*packages.Package{pkg}, p.typeNames, p.filter)
+	}
+
+	packageNames := make([]string, len(p.packageNames))
+	for i, packageName := range p.packageNames {
+		if packageName != "." {
+			packageNames[i] = packageName
+			continue
+		}
+		dir, err := filepath.Abs(p.config.dir)
 		if err != nil {
 			return nil, fmt.Errorf("get current directory failed: %v", err)
 		}
-		p.packageName, err = packageNameOfDir(dir)
+		packageNames[i], err = packageNameOfDir(dir)
 		if err != nil {
 			return nil, fmt.Errorf("parse package name failed: %v", err)
 		}
 	}
 
-	pkg, err := parsePackage(p.packageName, p.typeName)
+	pkg, err := parsePackages(p.config, packageNames, p.typeNames, p.filter)
 	return pkg, err
 }
 
@@ -109,90 +154,160 @@ func parsePackageImport(srcDir string) (string, error) {
 	return "", errOutsideGoPath
 }
 
-var (
-	buildFlags = flag.String("build_flags", "", "(package mode) Additional flags for go build.")
-)
-
-func parsePackage(packageName string, structName string) (*entity.JsonSchemaMetadata, error) {
-	pkg, err := loadPackage(packageName)
+func parsePackages(config loadConfig, packageNames []string, structNames []string,
+	filter *TypeFilter) (*entity.JsonSchemaMetadata, error) {
+	pkgs, err := loadPackages(config, packageNames...)
 	if err != nil {
 		return nil, fmt.Errorf("load package: %w", err)
 	}
+	return extractMetadataFromPackages(config, pkgs, structNames, filter)
+}
 
-	typeMetadata, err := extractMetadataFromPackage(pkg, structName)
-	if err != nil {
-		return nil, fmt.Errorf("extract typeMetadata from package: %w", err)
+func extractMetadataFromPackages(config loadConfig, pkgs []*packages.Package, structNames []string,
+	filter *TypeFilter) (*entity.JsonSchemaMetadata, error) {
+	var err error
+	if len(structNames) > 0 && len(pkgs) != 1 {
+		return nil, fmt.Errorf("types %s must be looked up in one package, found %d packages",
+			strings.Join(structNames, ", "), len(pkgs))
 	}
 
+	typeMetadata := entity.NewJsonSchemaMetadata()
+	var roots []*entity.DataTypeMetadata
+	for _, pkg := range pkgs {
+		pkgStructNames := structNames
+		if len(pkgStructNames) == 0 {
+			pkgStructNames, err = filter.filterTypes(config, pkg, exportedStructNames(pkg.Types))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, structName := range pkgStructNames {
+			root, err := extractMetadataFromPackage(typeMetadata, pkg, structName)
+			if err != nil {
+				return nil, fmt.Errorf("extract typeMetadata from package: %w", err)
+			}
+			roots = append(roots, root)
+		}
+	}
+
+	if len(roots) == 1 && len(structNames) == 1 {
+		typeMetadata.Root = roots[0]
+	} else {
+		typeMetadata.Roots = roots
+	}
 	return typeMetadata, nil
 }
 
-func loadPackage(packageName string) (*packages.Package, error) {
-	var buildFlagsSet []string
-	if *buildFlags != "" {
-		buildFlagsSet = strings.Split(*buildFlags, " ")
+// exportedStructNames returns names of all exported not generic structs declared in package
+func exportedStructNames(pkg *types.Package) []string {
+	var names []string
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !obj.Exported() || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+		if _, ok = named.Underlying().(*types.Struct); ok {
+			names = append(names, name)
+		}
 	}
+	return names
+}
 
-	cfg := &packages.Config{
-		Mode:       packages.NeedDeps | packages.NeedImports | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedEmbedFiles | packages.NeedSyntax,
-		BuildFlags: buildFlagsSet,
-	}
-	pkgs, err := packages.Load(cfg, packageName)
+func loadPackages(config loadConfig, packageNames ...string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(config.packagesConfig(), packageNames...)
 	if err != nil {
 		return nil, fmt.Errorf("load packages: %w", err)
 	}
 
-	if len(pkgs) != 1 {
-		return nil, fmt.Errorf("packages length must be 1: %d", len(pkgs))
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages found for %s", strings.Join(packageNames, " "))
 	}
 
-	if len(pkgs[0].Errors) > 0 {
-		errs := make([]error, len(pkgs[0].Errors))
-		for i, err := range pkgs[0].Errors {
-			errs[i] = err
+	var errs []error
+	for _, pkg := range pkgs {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
 		}
-
+	}
+	if len(errs) > 0 {
 		return nil, errors.Join(errs...)
 	}
 
-	return pkgs[0], nil
+	return pkgs, nil
 }
 
-func extractMetadataFromPackage(pkg *packages.Package, structName string) (*entity.JsonSchemaMetadata, error) {
-	obj := pkg.Types.Scope().Lookup(structName)
-	if obj == nil {
-		return nil, fmt.Errorf("struct %s does not exist", structName)
+func extractMetadataFromPackage(schemaMetadata *entity.JsonSchemaMetadata, pkg *packages.Package,
+	structName string) (*entity.DataTypeMetadata, error) {
+	typ, err := lookupType(pkg, structName)
+	if err != nil {
+		return nil, err
 	}
 
-	modelIface, err := parseStruct(obj)
+	root, err := parseStruct(schemaMetadata, typ, structName)
 	if err != nil {
 		return nil, err
 	}
 
-	return modelIface, nil
+	return root, nil
 }
 
-func parseStruct(obj types.Object) (*entity.JsonSchemaMetadata, error) {
-	named, ok := types.Unalias(obj.Type()).(*types.Named)
+// lookupType finds type in package scope. Instantiations of generic types like Page[User]
+// are evaluated as type expressions in scope of the package files, so imported packages
+// can be used in type arguments.
+func lookupType(pkg *packages.Package, structName string) (types.Type, error) {
+	if !strings.Contains(structName, "[") {
+		obj := pkg.Types.Scope().Lookup(structName)
+		if obj == nil {
+			return nil, fmt.Errorf("struct %s does not exist", structName)
+		}
+		return obj.Type(), nil
+	}
+
+	var errs []error
+	for _, file := range pkg.Syntax {
+		typeAndValue, err := types.Eval(pkg.Fset, pkg.Types, file.Name.End(), structName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !typeAndValue.IsType() {
+			return nil, fmt.Errorf("%s is not a type", structName)
+		}
+		return typeAndValue.Type, nil
+	}
+	return nil, fmt.Errorf("struct %s does not exist: %w", structName, errors.Join(errs...))
+}
+
+func parseStruct(schemaMetadata *entity.JsonSchemaMetadata, typ types.Type, structName string) (*entity.DataTypeMetadata, error) {
+	named, ok := types.Unalias(typ).(*types.Named)
 	if !ok {
-		return nil, fmt.Errorf("%s is not an struct. it is a %T", obj.Name(), obj.Type().Underlying())
+		return nil, fmt.Errorf("%s is not an struct. it is a %T", structName, typ.Underlying())
+	}
+
+	if named.TypeParams().Len() > 0 && named.TypeArgs().Len() == 0 {
+		return nil, fmt.Errorf("%s has unresolved type parameters %s, use instantiation like %s[...]",
+			structName, typeParamsString(named.TypeParams()), named.Obj().Name())
 	}
 
 	strct, ok := named.Underlying().(*types.Struct)
 	if !ok {
-		return nil, fmt.Errorf("%s is not an struct. it is a %T", obj.Name(), obj.Type().Underlying())
+		return nil, fmt.Errorf("%s is not an struct. it is a %T", structName, typ.Underlying())
 	}
 
-	rootMetadata := entity.NewDataTypeMetadata(obj.Pkg().Path(), obj.Name(), "struct", false)
+	obj := named.Obj()
+	rootMetadata := entity.NewDataTypeMetadata(obj.Pkg().Path(), namedTypeName(named), "struct", false)
 
-	mainMetadata := entity.NewJsonSchemaMetadata()
-
-	root, _, err := parseStructInRecursion(mainMetadata, strct, rootMetadata)
+	root, _, err := parseStructInRecursion(schemaMetadata, strct, rootMetadata)
 	if err != nil {
 		return nil, err
 	}
-	mainMetadata.Root = root
-	return mainMetadata, nil
+	return root, nil
 }
 
 func parseStructInRecursion(schemaMetadata *entity.JsonSchemaMetadata, typ types.Type, currentMetadata *entity.DataTypeMetadata) (metadata *entity.DataTypeMetadata, isStruct bool, err error) {
@@ -203,21 +318,38 @@ func parseStructInRecursion(schemaMetadata *entity.JsonSchemaMetadata, typ types
 	}()
 	metadata = currentMetadata
 
-	pointer, isPointer := typ.(*types.Pointer)
+	pointer, isPointer := types.Unalias(typ).(*types.Pointer)
 	if isPointer {
 		typ = pointer.Elem()
 	}
+	typ = types.Unalias(typ)
 	named, isNamed := typ.(*types.Named)
 	if isNamed {
 		obj := named.Obj()
 		//todo скорее всего неправильно выставлять "struct", могут быть другие типы для Named
-		metadata = entity.NewDataTypeMetadataWithBaseMetadata(currentMetadata, obj.Pkg().Path(), obj.Name(), "struct", isPointer)
+		metadata = entity.NewDataTypeMetadataWithBaseMetadata(currentMetadata, obj.Pkg().Path(), namedTypeName(named), "struct", isPointer)
 		typ = named.Underlying()
 	}
 
 	switch specificType := typ.(type) {
+	case *types.TypeParam:
+		return nil, false, fmt.Errorf("unresolved type parameter %s (%s)", specificType.Obj().Name(), specificType.Constraint())
 	case *types.Basic:
-		metadata = entity.NewDataTypeMetadataWithBaseMetadata(currentMetadata, "", specificType.String(), specificType.String(), false)
+		// Aliases byte and rune are named by their kind like reflect does
+		kind := types.Typ[specificType.Kind()].Name()
+		metadata = entity.NewDataTypeMetadataWithBaseMetadata(currentMetadata, "", kind, kind, false)
+		return metadata, false, nil
+	case *types.Slice:
+		return parseCollection(schemaMetadata, metadata, isNamed, "slice", specificType.Elem())
+	case *types.Array:
+		return parseCollection(schemaMetadata, metadata, isNamed, "array", specificType.Elem())
+	case *types.Map:
+		return parseCollection(schemaMetadata, metadata, isNamed, "map", specificType.Elem())
+	case *types.Interface:
+		if !isNamed {
+			metadata.Package = ""
+		}
+		metadata.TypeKind = "interface"
 		return metadata, false, nil
 	case *types.Struct:
 		if dataTypeMetadata, ok := schemaMetadata.Types[metadata.ID()]; ok {
@@ -252,15 +384,43 @@ func parseStructInRecursion(schemaMetadata *entity.JsonSchemaMetadata, typ types
 		}
 		return metadata, true, nil
 	default:
-		return nil, false, errors.New("incorrect and unexpected ype")
+		return nil, false, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+// parseCollection parses slice, array or map. Element of collection is its single node, for map it is type of values.
+// Named collections like json.RawMessage keep package and name of their type.
+func parseCollection(schemaMetadata *entity.JsonSchemaMetadata, metadata *entity.DataTypeMetadata, isNamed bool,
+	typeKind string, elem types.Type) (*entity.DataTypeMetadata, bool, error) {
+	elemMetadata, isStruct, err := parseStructInRecursion(schemaMetadata, elem, &entity.DataTypeMetadata{})
+	if err != nil {
+		return nil, false, err
+	}
+	if isStruct {
+		elemMetadata = entity.NewDataTypeRefMetadata(elemMetadata)
+	}
+	_, elemMetadata.IsPointer = types.Unalias(elem).(*types.Pointer)
+
+	if !isNamed {
+		metadata.Package = ""
 	}
+	metadata.TypeKind = typeKind
+	metadata.Nodes = []*entity.DataTypeMetadata{elemMetadata}
+	return metadata, false, nil
 }
 
 func parseTags(tg string) map[string][]string {
-	tagsResult := make(map[string][]string)
+	var tagsResult map[string][]string
 	tags := strings.Split(tg, " ")
 	for _, tag := range tags {
 		tmp := strings.Split(tag, ":")
+		if len(tmp) < 2 {
+			// field without tags
+			continue
+		}
+		if tagsResult == nil {
+			tagsResult = make(map[string][]string)
+		}
 		splited := strings.Split(tmp[1], ",")
 		length := len(splited)
 		if length > 1 {
@@ -273,3 +433,21 @@ func parseTags(tg string) map[string][]string {
 	}
 	return tagsResult
 }
+
+// namedTypeName returns name of the named type. Instantiated generic types get name with
+// type arguments, e.g. PageOfUser for Page[User]
+func namedTypeName(named *types.Named) string {
+	obj := named.Obj()
+	if named.TypeArgs().Len() == 0 {
+		return obj.Name()
+	}
+	return genericTypeName(types.TypeString(named, types.RelativeTo(obj.Pkg())))
+}
+
+func typeParamsString(typeParams *types.TypeParamList) string {
+	names := make([]string, typeParams.Len())
+	for i := 0; i < typeParams.Len(); i++ {
+		names[i] = typeParams.At(i).Obj().Name()
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
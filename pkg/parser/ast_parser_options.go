@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"golang.org/x/tools/go/packages"
+	"os"
+	"strings"
+)
+
+// AstParserOption configures how AstParser loads packages
+type AstParserOption func(config *loadConfig)
+
+// loadConfig contains settings passed to packages.Config
+type loadConfig struct {
+	buildTags  []string
+	buildFlags []string
+	env        []string
+	dir        string
+	overlay    map[string][]byte
+}
+
+// WithBuildTags sets build tags, so types guarded by //go:build constraints can be parsed
+func WithBuildTags(tags ...string) AstParserOption {
+	return func(config *loadConfig) {
+		config.buildTags = append(config.buildTags, tags...)
+	}
+}
+
+// WithBuildFlags sets additional flags for go build, e.g. -mod=vendor
+func WithBuildFlags(flags ...string) AstParserOption {
+	return func(config *loadConfig) {
+		config.buildFlags = append(config.buildFlags, flags...)
+	}
+}
+
+// WithEnv sets environment variables in form KEY=value, e.g. GOOS=windows, GOARCH=arm64 or GOFLAGS=-mod=mod.
+// Variables override the same variables of the current process environment.
+func WithEnv(env ...string) AstParserOption {
+	return func(config *loadConfig) {
+		config.env = append(config.env, env...)
+	}
+}
+
+// WithDir sets working directory used to resolve packages, so a module other than the current one can be parsed
+func WithDir(dir string) AstParserOption {
+	return func(config *loadConfig) {
+		config.dir = dir
+	}
+}
+
+// WithOverlay replaces contents of files by absolute path. Files that do not exist on disk are added to
+// their directory package.
+func WithOverlay(overlay map[string][]byte) AstParserOption {
+	return func(config *loadConfig) {
+		if config.overlay == nil {
+			config.overlay = make(map[string][]byte, len(overlay))
+		}
+		for filename, content := range overlay {
+			config.overlay[filename] = content
+		}
+	}
+}
+
+func newLoadConfig(opts []AstParserOption) loadConfig {
+	var config loadConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+func (c loadConfig) packagesConfig() *packages.Config {
+	buildFlags := c.buildFlags
+	if len(c.buildTags) > 0 {
+		buildFlags = append(buildFlags[:len(buildFlags):len(buildFlags)], "-tags="+strings.Join(c.buildTags, ","))
+	}
+
+	var env []string
+	if len(c.env) > 0 {
+		// The last value of duplicated variable is used
+		env = append(os.Environ(), c.env...)
+	}
+
+	return &packages.Config{
+		Mode:       packages.NeedName | packages.NeedDeps | packages.NeedImports | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedEmbedFiles | packages.NeedSyntax,
+		BuildFlags: buildFlags,
+		Env:        env,
+		Dir:        c.dir,
+		Overlay:    c.overlay,
+	}
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"sort"
+)
+
+// checkSources parses and type checks in memory sources of one package. Imports are resolved
+// from GOROOT sources, so go list is not called.
+func checkSources(packagePath string, sources map[string][]byte) (*packages.Package, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("no sources")
+	}
+
+	filenames := make([]string, 0, len(sources))
+	for filename := range sources {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(filenames))
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(fset, filename, sources[filename], parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	var errs []error
+	config := &types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			errs = append(errs, err)
+		},
+	}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	typesPackage, _ := config.Check(packagePath, fset, files, info)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("type check %s: %w", packagePath, errors.Join(errs...))
+	}
+
+	return &packages.Package{
+		ID:        packagePath,
+		Name:      typesPackage.Name(),
+		PkgPath:   packagePath,
+		Fset:      fset,
+		Syntax:    files,
+		Types:     typesPackage,
+		TypesInfo: info,
+	}, nil
+}
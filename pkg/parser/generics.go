@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// genericTypeName converts the name of an instantiated generic type into a name
+// that is usable as a definition key. Both reflect and go/types print instantiations
+// with package qualified type arguments, e.g. Page[github.com/x/y.User], which
+// becomes PageOfUser. Several type arguments are joined with And: Pair[int,string]
+// becomes PairOfIntAndString. Names without type arguments are returned as is.
+func genericTypeName(name string) string {
+	open := strings.IndexByte(name, '[')
+	if open <= 0 || !strings.HasSuffix(name, "]") {
+		return name
+	}
+	args := splitTypeArgs(name[open+1 : len(name)-1])
+	argNames := make([]string, len(args))
+	for i, arg := range args {
+		argNames[i] = typeArgName(arg)
+	}
+	return name[:open] + "Of" + strings.Join(argNames, "And")
+}
+
+// typeArgName returns readable name of a single type argument
+func typeArgName(arg string) string {
+	arg = strings.TrimSpace(arg)
+	switch {
+	case strings.HasPrefix(arg, "*"):
+		return typeArgName(arg[1:])
+	case strings.HasPrefix(arg, "[]"):
+		return typeArgName(arg[2:]) + "List"
+	case strings.HasPrefix(arg, "["):
+		// fixed size array [N]T
+		if end := strings.IndexByte(arg, ']'); end > 0 {
+			return typeArgName(arg[end+1:]) + "List"
+		}
+	case strings.HasPrefix(arg, "map["):
+		if end := matchingBracket(arg, len("map")); end > 0 {
+			return "MapOf" + typeArgName(arg[len("map["):end]) + "To" + typeArgName(arg[end+1:])
+		}
+	}
+
+	// Drop package path qualifier, it ends with the last dot before type arguments
+	qualified := arg
+	if open := strings.IndexByte(qualified, '['); open >= 0 {
+		qualified = qualified[:open]
+	}
+	if dot := strings.LastIndexByte(qualified, '.'); dot >= 0 {
+		arg = arg[dot+1:]
+	}
+	return exportedIdentifier(genericTypeName(arg))
+}
+
+// splitTypeArgs splits type arguments list by commas, that are not nested into brackets
+func splitTypeArgs(args string) []string {
+	var result []string
+	depth, start := 0, 0
+	for i, r := range args {
+		switch r {
+		case '[', '{', '(':
+			depth++
+		case ']', '}', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, args[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, args[start:])
+}
+
+// matchingBracket returns index of the bracket that closes bracket at position open
+func matchingBracket(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// exportedIdentifier removes all characters that are not allowed in identifier and
+// capitalizes the first letter, so interface {} becomes Interface and int becomes Int
+func exportedIdentifier(name string) string {
+	var builder strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if builder.Len() == 0 {
+				r = unicode.ToUpper(r)
+			}
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
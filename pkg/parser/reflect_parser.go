@@ -47,7 +47,15 @@ func parseTypeMetadata(schemaMetadata *entity.JsonSchemaMetadata,
 		}
 	}()
 	typeKind := t.Kind()
-	metadata = entity.NewDataTypeMetadata(t.PkgPath(), t.Name(), typeKind.String(), typeKind == reflect.Ptr)
+	metadata = entity.NewDataTypeMetadata(t.PkgPath(), genericTypeName(t.Name()), typeKind.String(), typeKind == reflect.Ptr)
+	// Element of collection is its single node, for map it is type of values
+	if typeKind == reflect.Slice || typeKind == reflect.Array || typeKind == reflect.Map {
+		elemMetadata, err := parseElementMetadata(schemaMetadata, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		metadata.Nodes = []*entity.DataTypeMetadata{elemMetadata}
+	}
 	// Only process structs
 	if t.Kind() == reflect.Struct {
 		// If data type metadata created then return it
@@ -80,7 +88,7 @@ func parseTypeMetadata(schemaMetadata *entity.JsonSchemaMetadata,
 			if nodeTypeKind == reflect.Struct {
 				nodeMetadata = entity.NewDataTypeRefMetadata(nodeTypeMetadata)
 			} else {
-				nodeMetadata = entity.NewDataTypeMetadata(fieldType.PkgPath(), fieldType.Name(),
+				nodeMetadata = entity.NewDataTypeMetadata(fieldType.PkgPath(), genericTypeName(fieldType.Name()),
 					nodeTypeKind.String(), nodeTypeKind == reflect.Ptr)
 				nodeMetadata.Nodes = nodeTypeMetadata.Nodes
 			}
@@ -93,6 +101,23 @@ func parseTypeMetadata(schemaMetadata *entity.JsonSchemaMetadata,
 	return metadata, nil
 }
 
+// parseElementMetadata parses type of collection element. Struct element is reference to its type metadata.
+func parseElementMetadata(schemaMetadata *entity.JsonSchemaMetadata, t reflect.Type) (*entity.DataTypeMetadata, error) {
+	isPointer := t.Kind() == reflect.Ptr
+	if isPointer {
+		t = t.Elem()
+	}
+	elemMetadata, err := parseTypeMetadata(schemaMetadata, t)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind() == reflect.Struct {
+		elemMetadata = entity.NewDataTypeRefMetadata(elemMetadata)
+	}
+	elemMetadata.IsPointer = isPointer
+	return elemMetadata, nil
+}
+
 func extractTags(tag reflect.StructTag) map[string][]string {
 	var tags map[string][]string
 	for _, key := range strings.Split(string(tag), " ") {
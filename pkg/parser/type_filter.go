@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"regexp"
+	"strings"
+)
+
+// TypeFilter selects root types, when all exported structs of packages are parsed.
+// Type is selected only if it matches all set conditions.
+type TypeFilter struct {
+	// NamePattern must match type name
+	NamePattern *regexp.Regexp
+	// Marker must be a line of type doc comment, e.g. "jsonschema:generate" for //jsonschema:generate
+	Marker string
+	// Implements is an interface in form <import path>.<name>, e.g. fmt.Stringer.
+	// Type matches, if the type or a pointer to it implements the interface.
+	Implements string
+}
+
+func (f *TypeFilter) filterTypes(config loadConfig, pkg *packages.Package, typeNames []string) ([]string, error) {
+	if f == nil {
+		return typeNames, nil
+	}
+
+	var iface *types.Interface
+	if f.Implements != "" {
+		var err error
+		iface, err = lookupInterface(config, pkg, f.Implements)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var markedTypes map[string]bool
+	if f.Marker != "" {
+		markedTypes = typesWithMarker(pkg.Syntax, f.Marker)
+	}
+
+	var result []string
+	for _, typeName := range typeNames {
+		if f.NamePattern != nil && !f.NamePattern.MatchString(typeName) {
+			continue
+		}
+		if markedTypes != nil && !markedTypes[typeName] {
+			continue
+		}
+		if iface != nil {
+			typ := pkg.Types.Scope().Lookup(typeName).Type()
+			if !types.Implements(typ, iface) && !types.Implements(types.NewPointer(typ), iface) {
+				continue
+			}
+		}
+		result = append(result, typeName)
+	}
+	return result, nil
+}
+
+// lookupInterface finds interface by qualified name in package, its dependencies or loads its package
+func lookupInterface(config loadConfig, pkg *packages.Package, qualifiedName string) (*types.Interface, error) {
+	dot := strings.LastIndexByte(qualifiedName, '.')
+	if dot <= 0 {
+		return nil, fmt.Errorf("interface %s must be in form <import path>.<name>", qualifiedName)
+	}
+	packagePath, name := qualifiedName[:dot], qualifiedName[dot+1:]
+
+	var ifacePackage *types.Package
+	packages.Visit([]*packages.Package{pkg}, func(visited *packages.Package) bool {
+		if visited.PkgPath == packagePath {
+			ifacePackage = visited.Types
+		}
+		return ifacePackage == nil
+	}, nil)
+	if ifacePackage == nil {
+		// Types of the package can implement interface without importing its package
+		pkgs, err := loadPackages(config, packagePath)
+		if err != nil {
+			return nil, fmt.Errorf("load interface package: %w", err)
+		}
+		ifacePackage = pkgs[0].Types
+	}
+
+	obj := ifacePackage.Scope().Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("interface %s does not exist", qualifiedName)
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an interface. it is a %T", qualifiedName, obj.Type().Underlying())
+	}
+	return iface, nil
+}
+
+// typesWithMarker returns names of types, that have marker line in doc comment
+func typesWithMarker(files []*ast.File, marker string) map[string]bool {
+	result := make(map[string]bool)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				if hasMarker(doc, marker) {
+					result[typeSpec.Name.Name] = true
+				}
+			}
+		}
+	}
+	return result
+}
+
+// hasMarker checks comment lines directly, because CommentGroup.Text drops directives like //jsonschema:generate
+func hasMarker(doc *ast.CommentGroup, marker string) bool {
+	if doc == nil {
+		return false
+	}
+	for _, comment := range doc.List {
+		if strings.TrimSpace(strings.TrimPrefix(comment.Text, "//")) == marker {
+			return true
+		}
+	}
+	return false
+}
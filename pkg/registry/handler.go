@@ -0,0 +1,243 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/compatibility"
+	"net/http"
+	"strconv"
+)
+
+// ContentType is media type of schema registry API
+const ContentType = "application/vnd.schemaregistry.v1+json"
+
+var errInvalidVersion = errors.New("invalid version")
+
+// errorCodes are error codes of schema registry API by error
+var errorCodes = []struct {
+	err    error
+	status int
+	code   int
+}{
+	{ErrSubjectNotFound, http.StatusNotFound, 40401},
+	{ErrVersionNotFound, http.StatusNotFound, 40402},
+	{ErrSchemaNotFound, http.StatusNotFound, 40403},
+	{ErrIncompatibleSchema, http.StatusConflict, 409},
+	{ErrInvalidSchema, http.StatusUnprocessableEntity, 42201},
+	{ErrInvalidSubject, http.StatusUnprocessableEntity, 42201},
+	{errInvalidVersion, http.StatusUnprocessableEntity, 42202},
+	{compatibility.ErrUnknownLevel, http.StatusUnprocessableEntity, 42203},
+}
+
+type schemaRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type configRequest struct {
+	Compatibility compatibility.Level `json:"compatibility"`
+}
+
+type errorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+type handler struct {
+	registry *Registry
+}
+
+// NewHandler returns handler of REST API compatible with endpoints of Confluent schema registry:
+// subjects, schemas by id, compatibility checks and compatibility configuration. Only JSON schema type
+// is supported.
+func NewHandler(registry *Registry) http.Handler {
+	h := &handler{registry: registry}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /subjects", h.subjects)
+	mux.HandleFunc("POST /subjects/{subject}", h.lookup)
+	mux.HandleFunc("GET /subjects/{subject}/versions", h.versions)
+	mux.HandleFunc("POST /subjects/{subject}/versions", h.register)
+	mux.HandleFunc("GET /subjects/{subject}/versions/{version}", h.version)
+	mux.HandleFunc("GET /subjects/{subject}/versions/{version}/schema", h.versionSchema)
+	mux.HandleFunc("GET /schemas/ids/{id}", h.schemaByID)
+	mux.HandleFunc("GET /schemas/ids/{id}/schema", h.rawSchemaByID)
+	mux.HandleFunc("POST /compatibility/subjects/{subject}/versions", h.compatibility)
+	mux.HandleFunc("POST /compatibility/subjects/{subject}/versions/{version}", h.compatibility)
+	mux.HandleFunc("GET /config", h.config)
+	mux.HandleFunc("PUT /config", h.setConfig)
+	mux.HandleFunc("GET /config/{subject}", h.config)
+	mux.HandleFunc("PUT /config/{subject}", h.setConfig)
+	return mux
+}
+
+func (h *handler) subjects(w http.ResponseWriter, _ *http.Request) {
+	subjects, err := h.registry.Subjects()
+	writeResponse(w, subjects, err)
+}
+
+func (h *handler) lookup(w http.ResponseWriter, r *http.Request) {
+	schema, err := readSchema(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	registered, err := h.registry.Lookup(r.PathValue("subject"), schema)
+	writeResponse(w, registered, err)
+}
+
+func (h *handler) versions(w http.ResponseWriter, r *http.Request) {
+	versions, err := h.registry.Versions(r.PathValue("subject"))
+	writeResponse(w, versions, err)
+}
+
+func (h *handler) register(w http.ResponseWriter, r *http.Request) {
+	schema, err := readSchema(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	registered, err := h.registry.Register(r.PathValue("subject"), schema)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, map[string]int{"id": registered.ID}, nil)
+}
+
+func (h *handler) version(w http.ResponseWriter, r *http.Request) {
+	registered, err := h.getVersion(r)
+	writeResponse(w, registered, err)
+}
+
+func (h *handler) versionSchema(w http.ResponseWriter, r *http.Request) {
+	registered, err := h.getVersion(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, json.RawMessage(registered.Schema), nil)
+}
+
+// getVersion returns version of subject, version is number or latest
+func (h *handler) getVersion(r *http.Request) (*Schema, error) {
+	subject, version := r.PathValue("subject"), r.PathValue("version")
+	if version == "latest" || version == "-1" {
+		return h.registry.Latest(subject)
+	}
+	number, err := strconv.Atoi(version)
+	if err != nil || number < 1 {
+		return nil, fmt.Errorf("%w: %s", errInvalidVersion, version)
+	}
+	return h.registry.Get(subject, number)
+}
+
+func (h *handler) schemaByID(w http.ResponseWriter, r *http.Request) {
+	schema, err := h.getSchemaByID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, schemaRequest{Schema: string(schema), SchemaType: SchemaTypeJSON}, nil)
+}
+
+func (h *handler) rawSchemaByID(w http.ResponseWriter, r *http.Request) {
+	schema, err := h.getSchemaByID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, json.RawMessage(schema), nil)
+}
+
+func (h *handler) getSchemaByID(r *http.Request) ([]byte, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: id %s", ErrSchemaNotFound, r.PathValue("id"))
+	}
+	return h.registry.SchemaByID(id)
+}
+
+// compatibility checks schema against the version or all versions of subject, incompatibility messages are
+// returned with verbose parameter
+func (h *handler) compatibility(w http.ResponseWriter, r *http.Request) {
+	schema, err := readSchema(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	subject := r.PathValue("subject")
+	if r.PathValue("version") == "" {
+		err = h.registry.CheckCompatibility(subject, schema)
+	} else {
+		var registered *Schema
+		if registered, err = h.getVersion(r); err != nil {
+			writeError(w, err)
+			return
+		}
+		err = h.registry.CheckCompatibilityWithVersion(subject, registered.Version, schema)
+	}
+	if err != nil && !errors.Is(err, ErrIncompatibleSchema) {
+		writeError(w, err)
+		return
+	}
+
+	response := map[string]any{"is_compatible": err == nil}
+	if err != nil && r.URL.Query().Get("verbose") == "true" {
+		response["messages"] = []string{err.Error()}
+	}
+	writeResponse(w, response, nil)
+}
+
+func (h *handler) config(w http.ResponseWriter, r *http.Request) {
+	level, err := h.registry.Compatibility(r.PathValue("subject"))
+	writeResponse(w, map[string]compatibility.Level{"compatibilityLevel": level}, err)
+}
+
+func (h *handler) setConfig(w http.ResponseWriter, r *http.Request) {
+	request := new(configRequest)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, fmt.Errorf("%w: %w", compatibility.ErrUnknownLevel, err))
+		return
+	}
+	level, err := compatibility.ParseLevel(string(request.Compatibility))
+	if err == nil {
+		err = h.registry.SetCompatibility(r.PathValue("subject"), level)
+	}
+	writeResponse(w, configRequest{Compatibility: level}, err)
+}
+
+// readSchema reads schema of request body
+func readSchema(r *http.Request) ([]byte, error) {
+	request := new(schemaRequest)
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	if request.SchemaType != "" && request.SchemaType != SchemaTypeJSON {
+		return nil, fmt.Errorf("%w: schema type %s is not supported", ErrInvalidSchema, request.SchemaType)
+	}
+	return []byte(request.Schema), nil
+}
+
+func writeResponse(w http.ResponseWriter, value any, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	response := errorResponse{ErrorCode: 50001, Message: err.Error()}
+	status := http.StatusInternalServerError
+	for _, errorCode := range errorCodes {
+		if errors.Is(err, errorCode.err) {
+			response.ErrorCode, status = errorCode.code, errorCode.status
+			break
+		}
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
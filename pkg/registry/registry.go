@@ -0,0 +1,432 @@
+// Package registry stores versions of schemas by subject in a directory like schema registry does.
+//
+// Layout of registry directory:
+//
+//	config.json                              default compatibility level
+//	schemas/<id>.json                        schema by global id
+//	subjects/<subject>/config.json           compatibility level of subject
+//	subjects/<subject>/versions/<version>.json
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/compatibility"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SchemaTypeJSON is type of JSON Schema in schema registry. Other types like AVRO are not supported.
+const SchemaTypeJSON = "JSON"
+
+var ErrInvalidSubject = errors.New("subject is empty")
+var ErrSubjectNotFound = errors.New("subject not found")
+var ErrVersionNotFound = errors.New("version not found")
+var ErrSchemaNotFound = errors.New("schema not found")
+var ErrInvalidSchema = errors.New("invalid schema")
+var ErrIncompatibleSchema = errors.New("schema is incompatible with previous versions")
+
+// Schema is registered version of schema
+type Schema struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+	// ID is global id of schema, it is the same for equal schemas of all subjects
+	ID         int    `json:"id"`
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+}
+
+type config struct {
+	CompatibilityLevel compatibility.Level `json:"compatibilityLevel"`
+}
+
+// Registry is schema registry in directory. Registry is safe for concurrent use in a process.
+type Registry struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New creates registry in directory. Directory is created, if it does not exist.
+func New(dir string) (*Registry, error) {
+	for _, subdir := range []string{"schemas", "subjects"} {
+		if err := os.MkdirAll(filepath.Join(dir, subdir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Registry{dir: dir}, nil
+}
+
+// Register registers schema as new version of subject, if schema is compatible with previous versions
+// by compatibility level of subject, see Compatibility. Registered version is returned, if subject
+// already has equal schema.
+func (r *Registry) Register(subject string, schema []byte) (*Schema, error) {
+	if subject == "" {
+		return nil, ErrInvalidSubject
+	}
+	canonical, err := canonicalize(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	registered, err := r.lookup(subject, canonical)
+	if err == nil {
+		return registered, nil
+	}
+	if !errors.Is(err, ErrSchemaNotFound) && !errors.Is(err, ErrSubjectNotFound) {
+		return nil, err
+	}
+	if err = r.checkCompatibility(subject, canonical); err != nil {
+		return nil, err
+	}
+
+	id, err := r.schemaID(canonical)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := r.versions(subject)
+	if err != nil && !errors.Is(err, ErrSubjectNotFound) {
+		return nil, err
+	}
+	version := 1
+	if len(versions) > 0 {
+		version = versions[len(versions)-1] + 1
+	}
+	registered = &Schema{Subject: subject, Version: version, ID: id, SchemaType: SchemaTypeJSON,
+		Schema: string(canonical)}
+	if err = writeJSON(r.versionFile(subject, version), registered); err != nil {
+		return nil, err
+	}
+	return registered, nil
+}
+
+// Generator generates JSON document of schema, e.g. jsonschema.SchemaGenerator
+type Generator interface {
+	ToJson() ([]byte, error)
+}
+
+// RegisterGenerator registers generated schema as new version of subject, e.g. at startup of service.
+// Registered version is returned, if subject already has the schema, see Registry.Register.
+func RegisterGenerator(r *Registry, g Generator, subject string) (*Schema, error) {
+	schema, err := g.ToJson()
+	if err != nil {
+		return nil, err
+	}
+	return r.Register(subject, schema)
+}
+
+// Lookup returns version of subject with schema
+func (r *Registry) Lookup(subject string, schema []byte) (*Schema, error) {
+	canonical, err := canonicalize(schema)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lookup(subject, canonical)
+}
+
+// Latest returns the latest version of subject
+func (r *Registry) Latest(subject string) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions, err := r.versions(subject)
+	if err != nil {
+		return nil, err
+	}
+	return r.get(subject, versions[len(versions)-1])
+}
+
+// Get returns version of subject
+func (r *Registry) Get(subject string, version int) (*Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.versions(subject); err != nil {
+		return nil, err
+	}
+	return r.get(subject, version)
+}
+
+// Versions returns versions of subject in ascending order
+func (r *Registry) Versions(subject string) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.versions(subject)
+}
+
+// Subjects returns sorted subjects with registered schemas
+func (r *Registry) Subjects() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries, err := os.ReadDir(filepath.Join(r.dir, "subjects"))
+	if err != nil {
+		return nil, err
+	}
+	subjects := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		subject, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if _, err = r.versions(subject); err == nil {
+			subjects = append(subjects, subject)
+		}
+	}
+	sort.Strings(subjects)
+	return subjects, nil
+}
+
+// SchemaByID returns schema by global id
+func (r *Registry) SchemaByID(id int) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schema, err := os.ReadFile(r.schemaFile(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: id %d", ErrSchemaNotFound, id)
+	}
+	return schema, err
+}
+
+// CheckCompatibility checks compatibility of schema with versions of subject by compatibility level of subject.
+// Returned error wraps ErrIncompatibleSchema and compatibility.IncompatibleError.
+func (r *Registry) CheckCompatibility(subject string, schema []byte) error {
+	canonical, err := canonicalize(schema)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.checkCompatibility(subject, canonical)
+}
+
+// CheckCompatibilityWithVersion checks compatibility of schema with the version of subject by compatibility level
+// of subject
+func (r *Registry) CheckCompatibilityWithVersion(subject string, version int, schema []byte) error {
+	canonical, err := canonicalize(schema)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	level, err := r.compatibility(subject)
+	if err != nil {
+		return err
+	}
+	registered, err := r.get(subject, version)
+	if err != nil {
+		return err
+	}
+	return checkCompatibility(level, [][]byte{[]byte(registered.Schema)}, canonical)
+}
+
+// Compatibility returns compatibility level of subject. Default level is returned for empty subject and
+// subjects without own level. Default level is compatibility.Backward, if it is not set.
+func (r *Registry) Compatibility(subject string) (compatibility.Level, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.compatibility(subject)
+}
+
+// SetCompatibility sets compatibility level of subject or default level for empty subject
+func (r *Registry) SetCompatibility(subject string, level compatibility.Level) error {
+	level, err := compatibility.ParseLevel(string(level))
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return writeJSON(r.configFile(subject), &config{CompatibilityLevel: level})
+}
+
+func (r *Registry) lookup(subject string, canonical []byte) (*Schema, error) {
+	versions, err := r.versions(subject)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		registered, err := r.get(subject, version)
+		if err != nil {
+			return nil, err
+		}
+		if registered.Schema == string(canonical) {
+			return registered, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: subject %s has no such schema", ErrSchemaNotFound, subject)
+}
+
+func (r *Registry) checkCompatibility(subject string, canonical []byte) error {
+	level, err := r.compatibility(subject)
+	if err != nil {
+		return err
+	}
+	versions, err := r.versions(subject)
+	if errors.Is(err, ErrSubjectNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	history := make([][]byte, 0, len(versions))
+	for _, version := range versions {
+		registered, err := r.get(subject, version)
+		if err != nil {
+			return err
+		}
+		history = append(history, []byte(registered.Schema))
+	}
+	return checkCompatibility(level, history, canonical)
+}
+
+// checkCompatibility checks schema by compatibility.Check. Schema, that can not be compared with history,
+// e.g. because of cycle of references, is ErrInvalidSchema.
+func checkCompatibility(level compatibility.Level, history [][]byte, schema []byte) error {
+	err := compatibility.Check(level, history, schema)
+	var incompatible *compatibility.IncompatibleError
+	switch {
+	case err == nil, errors.Is(err, compatibility.ErrUnknownLevel):
+		return err
+	case errors.As(err, &incompatible):
+		return fmt.Errorf("%w: %w", ErrIncompatibleSchema, err)
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+}
+
+func (r *Registry) compatibility(subject string) (compatibility.Level, error) {
+	for _, filename := range []string{r.configFile(subject), r.configFile("")} {
+		var subjectConfig config
+		err := readJSON(filename, &subjectConfig)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return subjectConfig.CompatibilityLevel, nil
+	}
+	return compatibility.Backward, nil
+}
+
+func (r *Registry) versions(subject string) ([]int, error) {
+	entries, err := os.ReadDir(filepath.Join(r.subjectDir(subject), "versions"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSubjectNotFound, subject)
+	}
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		version, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".json"))
+		if err == nil {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSubjectNotFound, subject)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+func (r *Registry) get(subject string, version int) (*Schema, error) {
+	registered := new(Schema)
+	err := readJSON(r.versionFile(subject, version), registered)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s version %d", ErrVersionNotFound, subject, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return registered, nil
+}
+
+// schemaID returns global id of schema. Schema gets new id, if it is not registered in any subject.
+func (r *Registry) schemaID(canonical []byte) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(r.dir, "schemas"))
+	if err != nil {
+		return 0, err
+	}
+	maxID := 0
+	for _, entry := range entries {
+		id, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		schema, err := os.ReadFile(r.schemaFile(id))
+		if err != nil {
+			return 0, err
+		}
+		if bytes.Equal(schema, canonical) {
+			return id, nil
+		}
+		maxID = max(maxID, id)
+	}
+	if err = os.WriteFile(r.schemaFile(maxID+1), canonical, 0o644); err != nil {
+		return 0, err
+	}
+	return maxID + 1, nil
+}
+
+func (r *Registry) subjectDir(subject string) string {
+	// Subject can contain separators of path. Dots are escaped too, so subjects . and .. are not parent
+	// directories.
+	return filepath.Join(r.dir, "subjects", strings.ReplaceAll(url.PathEscape(subject), ".", "%2E"))
+}
+
+func (r *Registry) versionFile(subject string, version int) string {
+	return filepath.Join(r.subjectDir(subject), "versions", strconv.Itoa(version)+".json")
+}
+
+func (r *Registry) schemaFile(id int) string {
+	return filepath.Join(r.dir, "schemas", strconv.Itoa(id)+".json")
+}
+
+func (r *Registry) configFile(subject string) string {
+	if subject == "" {
+		return filepath.Join(r.dir, "config.json")
+	}
+	return filepath.Join(r.subjectDir(subject), "config.json")
+}
+
+// canonicalize returns compact JSON of schema with sorted keys, so equal schemas have equal canonical form
+func canonicalize(schema []byte) ([]byte, error) {
+	var document any
+	if err := json.Unmarshal(schema, &document); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	switch document.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, fmt.Errorf("%w: schema must be object or boolean", ErrInvalidSchema)
+	}
+	return json.Marshal(document)
+}
+
+func readJSON(filename string, value any) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func writeJSON(filename string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
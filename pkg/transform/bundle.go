@@ -0,0 +1,401 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BundleFile bundles schema file with files it references, see Bundle.
+// Referenced files must be in directory of the schema or its subdirectories.
+func BundleFile(filename string) (*entity.JSONSchema, error) {
+	return Bundle(os.DirFS(filepath.Dir(filename)), filepath.Base(filename))
+}
+
+// Bundle loads root schema from file name of fsys and every file of fsys it references by relative $ref,
+// e.g. common.json#/$defs/Money. References by absolute URI are resolved against $id, so URI in directory
+// of root $id is file of fsys too. Every referenced file is embedded into $defs of root schema under name
+// of the file without extension, e.g. common, so bundle is a single self-contained schema document.
+// Embedded files keep all their keywords, only references are rewritten.
+//
+// File with $id or with $schema other than $schema of root schema is embedded as schema resource, that keeps
+// $schema and gets absolute $id, and references to it are rewritten to this $id, e.g.
+// https://example.com/common.json#/$defs/Money. Other files become part of root schema resource and references
+// to them are rewritten to local references, e.g. #/$defs/common/$defs/Money. References by anchor to files,
+// that are not schema resources, are not supported. References to files out of fsys are kept as is.
+func Bundle(fsys fs.FS, name string) (*entity.JSONSchema, error) {
+	root, err := loadSchema(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	rootURI := "file:///" + path.Clean(name)
+	if root.ID != nil && isAbsoluteURI(*root.ID) {
+		rootURI = *root.ID
+	}
+
+	rootDocument := &bundledDocument{filename: name, uri: rootURI, resource: true}
+	b := &bundler{
+		fsys:    fsys,
+		dir:     path.Dir(path.Clean(name)),
+		rootDir: rootURI[:strings.LastIndex(rootURI, "/")+1],
+		root:    rootDocument,
+		byURI:   map[string]*bundledDocument{rootURI: rootDocument, "file:///" + path.Clean(name): rootDocument},
+		names:   make(map[string]bool),
+	}
+	if root.ID != nil && isAbsoluteURI(*root.ID) {
+		b.rootID = *root.ID
+	}
+	if root.Schema != nil {
+		b.dialect = string(*root.Schema)
+	}
+	for _, defName := range root.Defs.Keys() {
+		b.names[defName] = true
+	}
+	err = walkSchemas(root, func(schema entity.DataType) error {
+		refSchema, ok := schema.(*entity.JSONSchema)
+		if !ok || refSchema.Ref == nil {
+			return nil
+		}
+		ref, err := b.bundleRef(*refSchema.Ref, rootDocument)
+		if err != nil {
+			return fmt.Errorf("reference %s: %w", *refSchema.Ref, err)
+		}
+		refSchema.Ref = &ref
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	// Documents are embedded, after references of root are rewritten
+	for i := 0; i < len(b.documents); i++ {
+		document := b.documents[i]
+		if err = b.rewriteRefs(document.schema, document); err != nil {
+			return nil, fmt.Errorf("%s: %w", document.filename, err)
+		}
+	}
+	for _, document := range b.documents {
+		root.AddDefinition(document.name, &rawSchema{value: document.schema})
+	}
+	if err = b.checkRefs(root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+type bundler struct {
+	fsys fs.FS
+	// dir is directory of root schema in fsys
+	dir string
+	// rootDir is URI of directory of root schema, URIs in it are files of fsys
+	rootDir string
+	// rootID is absolute $id of root schema
+	rootID string
+	// dialect is $schema of root schema
+	dialect string
+	root    *bundledDocument
+	// byURI are documents of bundle by their URI
+	byURI map[string]*bundledDocument
+	// names are names of definitions of root schema
+	names     map[string]bool
+	documents []*bundledDocument
+	// refs are rewritten references with JSON Pointer in bundle, they point to
+	refs []bundledRef
+}
+
+type bundledDocument struct {
+	filename string
+	// uri is URI, that references of document are resolved against
+	uri  string
+	name string
+	// pointer is JSON Pointer of document in bundle
+	pointer string
+	// resource is set for root schema and documents embedded as schema resources with own $id
+	resource bool
+	// schema is document decoded by entity.UnmarshalOrderedJSON
+	schema any
+}
+
+type bundledRef struct {
+	filename string
+	ref      string
+	pointer  string
+}
+
+// rewriteRefs rewrites references of embedded document to references of bundle
+func (b *bundler) rewriteRefs(schema any, document *bundledDocument) error {
+	obj, ok := schema.(*entity.OrderedMap[any])
+	if !ok {
+		return nil
+	}
+	if value, ok := obj.Get("$ref"); ok {
+		if ref, ok := value.(string); ok {
+			bundled, err := b.bundleRef(ref, document)
+			if err != nil {
+				return fmt.Errorf("reference %s: %w", ref, err)
+			}
+			obj.Set("$ref", bundled)
+		}
+	}
+	return entity.EachSubschema(obj, func(subschema any, _ string) error {
+		return b.rewriteRefs(subschema, document)
+	})
+}
+
+// bundleRef returns reference of bundle for reference of document
+func (b *bundler) bundleRef(ref string, document *bundledDocument) (string, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	baseURL, err := url.Parse(document.uri)
+	if err != nil {
+		return "", err
+	}
+	resolved := baseURL.ResolveReference(refURL)
+	fragment := resolved.Fragment
+	resolved.Fragment, resolved.RawFragment = "", ""
+	resolvedURI := resolved.String()
+	isAnchor := fragment != "" && !strings.HasPrefix(fragment, "/")
+
+	target, ok := b.byURI[resolvedURI]
+	if !ok {
+		filename, isFile := strings.CutPrefix(resolvedURI, b.rootDir)
+		if !isFile || strings.HasPrefix(filename, "../") || isAnchor {
+			return ref, nil
+		}
+		target, err = b.embed(filename, resolvedURI)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if isAnchor {
+		// Anchors of documents, that are not schema resources, are not unique in bundle
+		if !target.resource {
+			return "", fmt.Errorf("reference by anchor %s to document without $id is not supported", fragment)
+		}
+		return b.resourceRef(document, target, fragment)
+	}
+	b.refs = append(b.refs, bundledRef{filename: document.filename, ref: ref, pointer: target.pointer + fragment})
+	if !target.resource {
+		// Document is part of root schema resource
+		return b.resourceRef(document, b.root, target.pointer+fragment)
+	}
+	return b.resourceRef(document, target, fragment)
+}
+
+// resourceRef returns reference from document to fragment of schema resource
+func (b *bundler) resourceRef(document, resource *bundledDocument, fragment string) (string, error) {
+	documentResource := b.root
+	if document.resource {
+		documentResource = document
+	}
+	if documentResource == resource {
+		return "#" + fragment, nil
+	}
+	uri := resource.uri
+	if resource == b.root {
+		if b.rootID == "" {
+			return "", errors.New("reference from schema resource to root schema requires absolute $id of root schema")
+		}
+		uri = b.rootID
+	}
+	if fragment == "" {
+		return uri, nil
+	}
+	return uri + "#" + fragment, nil
+}
+
+// embed loads document of file, that is relative to directory of root schema
+func (b *bundler) embed(filename, uri string) (*bundledDocument, error) {
+	id, schema, err := loadDocument(b.fsys, path.Join(b.dir, filename))
+	if err != nil {
+		return nil, err
+	}
+	// References of document are resolved against its $id
+	documentURI := uri
+	if id != "" {
+		documentURI, err = resolveID(uri, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name := strings.TrimSuffix(path.Base(filename), ".json")
+	for i := 2; b.names[name]; i++ {
+		name = strings.TrimSuffix(path.Base(filename), ".json") + strconv.Itoa(i)
+	}
+	b.names[name] = true
+
+	document := &bundledDocument{filename: filename, uri: documentURI, name: name,
+		pointer: "/$defs/" + escapePointer(name), schema: schema}
+	if obj, ok := schema.(*entity.OrderedMap[any]); ok {
+		dialect, hasDialect := obj.Get("$schema")
+		document.resource = id != "" || (hasDialect && dialect != b.dialect)
+		if document.resource {
+			// Resource keeps its identity, $id goes first like in entity schemas
+			withID := entity.NewOrderedMap[any]().Set("$id", documentURI)
+			for _, keyword := range obj.Keys() {
+				withID.Set(keyword, get(obj, keyword))
+			}
+			document.schema = withID
+		} else {
+			// Dialect of document is dialect of root schema
+			obj.Delete("$schema")
+		}
+	}
+	b.documents = append(b.documents, document)
+	b.byURI[uri] = document
+	b.byURI[documentURI] = document
+	return document, nil
+}
+
+// checkRefs checks, that every rewritten reference points to subschema of bundle. Keywords, that entity schemas
+// do not have, are lost in root schema, so references into them can not be resolved.
+func (b *bundler) checkRefs(root *entity.JSONSchema) error {
+	data, err := json.Marshal(root)
+	if err != nil {
+		return err
+	}
+	var bundle any
+	if err = json.Unmarshal(data, &bundle); err != nil {
+		return err
+	}
+	for _, ref := range b.refs {
+		if !pointerExists(bundle, ref.pointer) {
+			return fmt.Errorf("%s: %w: %s of reference %s", ref.filename, ErrDefinitionNotFound, ref.pointer,
+				ref.ref)
+		}
+	}
+	return nil
+}
+
+// pointerExists reports whether JSON Pointer points to value of document
+func pointerExists(document any, pointer string) bool {
+	if pointer == "" {
+		return true
+	}
+	value := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch container := value.(type) {
+		case map[string]any:
+			child, ok := container[token]
+			if !ok {
+				return false
+			}
+			value = child
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(container) {
+				return false
+			}
+			value = container[index]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// resolveID returns URI of document with id, that was loaded from uri
+func resolveID(uri, id string) (string, error) {
+	baseURL, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	idURL, err := url.Parse(id)
+	if err != nil {
+		return "", err
+	}
+	resolved := baseURL.ResolveReference(idURL)
+	resolved.Fragment, resolved.RawFragment = "", ""
+	return resolved.String(), nil
+}
+
+func loadSchema(fsys fs.FS, name string) (*entity.JSONSchema, error) {
+	data, err := fs.ReadFile(fsys, path.Clean(name))
+	if err != nil {
+		return nil, err
+	}
+	schema := new(entity.JSONSchema)
+	if err = json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", name, err)
+	}
+	return schema, nil
+}
+
+// loadDocument loads schema document of file and returns its $id. Document is decoded by
+// entity.UnmarshalOrderedJSON, so it keeps all keywords and their order.
+func loadDocument(fsys fs.FS, name string) (string, any, error) {
+	data, err := fs.ReadFile(fsys, path.Clean(name))
+	if err != nil {
+		return "", nil, err
+	}
+	schema, err := entity.UnmarshalOrderedJSON(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("unmarshal %s: %w", name, err)
+	}
+	switch schema := schema.(type) {
+	case *entity.OrderedMap[any]:
+		id, _ := get(schema, "$id").(string)
+		return id, schema, nil
+	case bool:
+		return "", schema, nil
+	}
+	return "", nil, fmt.Errorf("unmarshal %s: schema must be object or boolean", name)
+}
+
+func isAbsoluteURI(uri string) bool {
+	parsed, err := url.Parse(uri)
+	return err == nil && parsed.IsAbs()
+}
+
+// rawSchema is embedded document decoded by entity.UnmarshalOrderedJSON. It keeps keywords, that entity schemas
+// do not have, e.g. definitions of draft 07.
+type rawSchema struct {
+	value any
+}
+
+func (s *rawSchema) IsType(dataType entity.JSONSchemaDataType) bool {
+	return slices.Contains(s.types(), dataType)
+}
+
+func (s *rawSchema) IsTypes(dataTypes []entity.JSONSchemaDataType) bool {
+	return slices.Equal(s.types(), dataTypes)
+}
+
+func (s *rawSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.value)
+}
+
+func (s *rawSchema) types() []entity.JSONSchemaDataType {
+	obj, _ := s.value.(*entity.OrderedMap[any])
+	var dataTypes []entity.JSONSchemaDataType
+	switch value := get(obj, "type").(type) {
+	case string:
+		dataTypes = append(dataTypes, entity.JSONSchemaDataType(value))
+	case []any:
+		for _, dataType := range value {
+			if dataType, ok := dataType.(string); ok {
+				dataTypes = append(dataTypes, entity.JSONSchemaDataType(dataType))
+			}
+		}
+	}
+	return dataTypes
+}
+
+// get returns value of keyword or nil
+func get(obj *entity.OrderedMap[any], keyword string) any {
+	value, _ := obj.Get(keyword)
+	return value
+}
@@ -0,0 +1,147 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"slices"
+)
+
+var ErrDefinitionNotFound = errors.New("definition not found")
+
+// InlineRefs replaces every reference into $defs by copy of referenced definition and removes definitions,
+// that are not referenced anymore. References of recursive types are kept, since recursion can not be inlined.
+// References in keywords like items are kept, if definition is not an object schema, because such keywords
+// hold only JSONSchema. Keywords next to reference are kept together with inlined definition in allOf.
+//
+// InlineRefs changes schema, so it can be used as AfterConvert hook of SchemaGenerator.
+func InlineRefs(schema *entity.JSONSchema) error {
+	return inlineDefinitions(schema, func(string) bool { return true })
+}
+
+// InlineSingleRefs inlines definitions, that are referenced only once, and removes unreachable definitions.
+// Definitions with several references are kept. See InlineRefs and RefCounts.
+func InlineSingleRefs(schema *entity.JSONSchema) error {
+	counts, err := RefCounts(schema)
+	if err != nil {
+		return err
+	}
+	return inlineDefinitions(schema, func(name string) bool { return counts[name] == 1 })
+}
+
+// inlineDefinitions inlines references to definitions, that match filter, and removes definitions,
+// that are not referenced anymore
+func inlineDefinitions(schema *entity.JSONSchema, filter func(name string) bool) error {
+	in := &inliner{defs: schema.Defs, filter: filter, kept: make(map[string]bool)}
+	// Reference of root schema is inlined into allOf like any reference with sibling keywords
+	if _, err := in.inline("", schema, true); err != nil {
+		return err
+	}
+
+	// Kept definitions can reference other definitions
+	inlined := make(map[string]bool)
+	for done := false; !done; {
+		done = true
+		for _, name := range in.defs.Keys() {
+			if !in.kept[name] || inlined[name] {
+				continue
+			}
+			done = false
+			inlined[name] = true
+			def, _ := in.defs.Get(name)
+			in.stack = []string{name}
+			if err := in.inlineSubschemas("/$defs/"+escapePointer(name), def); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range in.defs.Keys() {
+		if !in.kept[name] {
+			in.defs.Delete(name)
+		}
+	}
+	if in.defs.Len() == 0 {
+		schema.Defs = nil
+	}
+	return nil
+}
+
+type inliner struct {
+	defs *entity.OrderedMap[entity.DataType]
+	// filter selects definitions to inline
+	filter func(name string) bool
+	// kept are definitions, whose references are kept
+	kept map[string]bool
+	// stack are definitions being inlined
+	stack []string
+}
+
+func (in *inliner) inlineSubschemas(location string, schema entity.DataType) error {
+	return eachSubschema(schema, func(subschemaLocation string, subschema entity.DataType,
+		onlyJSONSchema bool) (entity.DataType, error) {
+		return in.inline(location+subschemaLocation, subschema, onlyJSONSchema)
+	})
+}
+
+// inline returns subschema with inlined references
+func (in *inliner) inline(location string, schema entity.DataType, onlyJSONSchema bool) (entity.DataType, error) {
+	refSchema, ok := schema.(*entity.JSONSchema)
+	if !ok || refSchema.Ref == nil {
+		return schema, in.inlineSubschemas(location, schema)
+	}
+	name, ok := definitionName(*refSchema.Ref)
+	if !ok {
+		return schema, in.inlineSubschemas(location, schema)
+	}
+	def, ok := in.defs.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s of reference at %s", ErrDefinitionNotFound, name, location)
+	}
+
+	siblings, err := hasSiblingKeywords(refSchema)
+	if err != nil {
+		return nil, err
+	}
+	_, isObject := toJSONSchema(def)
+	// Reference is kept for recursive definitions and definitions, that can not be merged into JSONSchema
+	if !in.filter(name) || slices.Contains(in.stack, name) || (!isObject && (siblings || onlyJSONSchema)) {
+		in.kept[name] = true
+		return schema, in.inlineSubschemas(location, schema)
+	}
+
+	inlined, err := copySchema(def)
+	if err != nil {
+		return nil, fmt.Errorf("copy definition %s: %w", name, err)
+	}
+	in.stack = append(in.stack, name)
+	err = in.inlineSubschemas(location, inlined)
+	in.stack = in.stack[:len(in.stack)-1]
+	if err != nil {
+		return nil, err
+	}
+	if !siblings {
+		return inlined, nil
+	}
+
+	// Keywords next to reference apply together with definition
+	refSchema.Ref = nil
+	if err = in.inlineSubschemas(location, refSchema); err != nil {
+		return nil, err
+	}
+	inlinedJSONSchema, _ := toJSONSchema(inlined)
+	refSchema.AllOf = append(refSchema.AllOf, inlinedJSONSchema)
+	return refSchema, nil
+}
+
+// hasSiblingKeywords reports whether reference schema has keywords except $ref
+func hasSiblingKeywords(refSchema *entity.JSONSchema) (bool, error) {
+	withoutRef := *refSchema
+	withoutRef.Ref = nil
+	data, err := json.Marshal(&withoutRef)
+	if err != nil {
+		return false, err
+	}
+	return string(data) != "{}", nil
+}
@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+)
+
+// RefCounts walks references into $defs from root schema and returns count of references to every reachable
+// definition. References from unreachable definitions are not counted. Definitions of bundle without root
+// schema are not reachable.
+func RefCounts(schema *entity.JSONSchema) (map[string]int, error) {
+	counts := make(map[string]int)
+	var walk func(location string, subschema entity.DataType) error
+	walk = func(location string, subschema entity.DataType) error {
+		if refSchema, ok := subschema.(*entity.JSONSchema); ok && refSchema.Ref != nil {
+			if name, ok := definitionName(*refSchema.Ref); ok {
+				def, ok := schema.Defs.Get(name)
+				if !ok {
+					return fmt.Errorf("%w: %s of reference at %s", ErrDefinitionNotFound, name, location)
+				}
+				counts[name]++
+				// Definition is walked once, when it is reached first time
+				if counts[name] == 1 {
+					if err := walk("/$defs/"+escapePointer(name), def); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return eachSubschema(subschema, func(subschemaLocation string, subschema entity.DataType,
+			_ bool) (entity.DataType, error) {
+			return subschema, walk(location+subschemaLocation, subschema)
+		})
+	}
+	if err := walk("", schema); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// PruneDefinitions removes definitions, that are not reachable by references from root schema.
+// It can be used as AfterConvert hook of SchemaGenerator, but not for bundles, see RefCounts.
+func PruneDefinitions(schema *entity.JSONSchema) error {
+	counts, err := RefCounts(schema)
+	if err != nil {
+		return err
+	}
+	for _, name := range schema.Defs.Keys() {
+		if counts[name] == 0 {
+			schema.Defs.Delete(name)
+		}
+	}
+	if schema.Defs.Len() == 0 {
+		schema.Defs = nil
+	}
+	return nil
+}
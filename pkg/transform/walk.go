@@ -0,0 +1,188 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"sort"
+	"strings"
+)
+
+// visitFunc is called for subschema at location, that is JSON Pointer relative to parent schema.
+// It returns subschema, that replaces the visited one. Subschemas of keywords like items are JSONSchema,
+// so onlyJSONSchema is set and replacement must be JSONSchema or ObjectSchema, other replacements are ignored.
+type visitFunc func(location string, subschema entity.DataType, onlyJSONSchema bool) (entity.DataType, error)
+
+// eachSubschema calls visit for every direct subschema of schema except definitions
+func eachSubschema(schema entity.DataType, visit visitFunc) error {
+	switch schema := schema.(type) {
+	case *entity.JSONSchema:
+		return eachObjectSubschema(&schema.ObjectSchema, visit)
+	case *entity.ObjectSchema:
+		return eachObjectSubschema(schema, visit)
+	case *entity.ArraySchema:
+		if err := visitJSONSchema("/items", &schema.Items, visit); err != nil {
+			return err
+		}
+		if err := visitJSONSchemas("/prefixItems", schema.PrefixItems, visit); err != nil {
+			return err
+		}
+		if err := visitJSONSchema("/contains", &schema.Contains, visit); err != nil {
+			return err
+		}
+		return visitJSONSchema("/unevaluatedItems", &schema.UnevaluatedItems, visit)
+	case *entity.StringSchema:
+		return visitJSONSchema("/contentSchema", &schema.ContentSchema, visit)
+	}
+	return nil
+}
+
+// walkSchemas calls fn for schema and all its subschemas including definitions
+func walkSchemas(schema entity.DataType, fn func(schema entity.DataType) error) error {
+	if err := fn(schema); err != nil {
+		return err
+	}
+	if jsonSchema, ok := schema.(*entity.JSONSchema); ok {
+		for _, name := range jsonSchema.Defs.Keys() {
+			def, _ := jsonSchema.Defs.Get(name)
+			if err := walkSchemas(def, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return eachSubschema(schema, func(_ string, subschema entity.DataType, _ bool) (entity.DataType, error) {
+		return subschema, walkSchemas(subschema, fn)
+	})
+}
+
+func eachObjectSubschema(schema *entity.ObjectSchema, visit visitFunc) error {
+	if err := visitDataTypes("/properties", schema.Properties, visit); err != nil {
+		return err
+	}
+	if err := visitDataTypes("/patternProperties", schema.PatternProperties, visit); err != nil {
+		return err
+	}
+	if schema.AdditionalProperties != nil {
+		if err := visitJSONSchema("/additionalProperties", &schema.AdditionalProperties.Schema, visit); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(schema.Dependencies) {
+		dependency := schema.Dependencies[name]
+		if dependency == nil {
+			continue
+		}
+		location := "/dependencies/" + escapePointer(name)
+		if err := visitJSONSchema(location, &dependency.SchemaDependency, visit); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(schema.DependentSchemas) {
+		subschema := schema.DependentSchemas[name]
+		if err := visitJSONSchema("/dependentSchemas/"+escapePointer(name), &subschema, visit); err != nil {
+			return err
+		}
+		schema.DependentSchemas[name] = subschema
+	}
+
+	keywords := []struct {
+		location  string
+		subschema **entity.JSONSchema
+	}{
+		{"/propertyNames", &schema.PropertyNames},
+		{"/if", &schema.If},
+		{"/then", &schema.Then},
+		{"/else", &schema.Else},
+		{"/not", &schema.Not},
+	}
+	for _, keyword := range keywords {
+		if err := visitJSONSchema(keyword.location, keyword.subschema, visit); err != nil {
+			return err
+		}
+	}
+	if err := visitJSONSchemas("/allOf", schema.AllOf, visit); err != nil {
+		return err
+	}
+	if err := visitJSONSchemas("/anyOf", schema.AnyOf, visit); err != nil {
+		return err
+	}
+	return visitJSONSchemas("/oneOf", schema.OneOf, visit)
+}
+
+func visitDataTypes(location string, schemas *entity.OrderedMap[entity.DataType], visit visitFunc) error {
+	for _, name := range schemas.Keys() {
+		subschema, _ := schemas.Get(name)
+		replacement, err := visit(location+"/"+escapePointer(name), subschema, false)
+		if err != nil {
+			return err
+		}
+		schemas.Set(name, replacement)
+	}
+	return nil
+}
+
+func visitJSONSchemas(location string, schemas []*entity.JSONSchema, visit visitFunc) error {
+	for i := range schemas {
+		if err := visitJSONSchema(fmt.Sprintf("%s/%d", location, i), &schemas[i], visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func visitJSONSchema(location string, subschema **entity.JSONSchema, visit visitFunc) error {
+	if *subschema == nil {
+		return nil
+	}
+	replacement, err := visit(location, *subschema, true)
+	if err != nil {
+		return err
+	}
+	if jsonSchema, ok := toJSONSchema(replacement); ok {
+		*subschema = jsonSchema
+	}
+	return nil
+}
+
+// toJSONSchema returns schema as JSONSchema. Only JSONSchema and ObjectSchema can be converted
+// without loss of keywords.
+func toJSONSchema(schema entity.DataType) (*entity.JSONSchema, bool) {
+	switch schema := schema.(type) {
+	case *entity.JSONSchema:
+		return schema, true
+	case *entity.ObjectSchema:
+		return &entity.JSONSchema{ObjectSchema: *schema}, true
+	}
+	return nil, false
+}
+
+// copySchema returns deep copy of schema
+func copySchema(schema entity.DataType) (entity.DataType, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	return entity.UnmarshalDataType(data)
+}
+
+// definitionName returns name of definition, that ref points to, if ref is local reference into $defs
+func definitionName(ref string) (string, bool) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok || strings.Contains(name, "/") {
+		return "", false
+	}
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name), true
+}
+
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,758 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"math/big"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// node is a compiled schema
+type node struct {
+	// location is absolute location of schema: <base uri>#<json pointer>
+	location string
+	// resource is base URI of schema resource, if schema is root of resource (document or schema with $id)
+	resource *string
+	// always is set for boolean schemas
+	always *bool
+
+	ref        *node
+	dynamicRef *node
+	// refKeyword is $recursiveRef in draft 2019-09, $dynamicRef in later drafts
+	dynamicRefKeyword string
+	// dynamicRefAnchor is set, if dynamic reference targets dynamic anchor and is resolved in dynamic scope.
+	// It is empty string for $recursiveRef.
+	dynamicRefAnchor *string
+
+	types    []string
+	enum     []any
+	hasEnum  bool
+	constant any
+	hasConst bool
+
+	multipleOf           *big.Rat
+	maximum              *big.Rat
+	exclusiveMaximum     *big.Rat
+	minimum              *big.Rat
+	exclusiveMinimum     *big.Rat
+	exclusiveMaximumBool bool // draft-04 form of exclusiveMaximum
+	exclusiveMinimumBool bool // draft-04 form of exclusiveMinimum
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	prefixItems      []*node
+	prefixKeyword    string // prefixItems, or items in drafts before 2020-12
+	items            *node
+	itemsKeyword     string // items, or additionalItems in drafts before 2020-12
+	contains         *node
+	minContains      *int
+	maxContains      *int
+	minItems         *int
+	maxItems         *int
+	uniqueItems      bool
+	unevaluatedItems *node
+
+	properties            map[string]*node
+	patternProperties     []*patternNode
+	additionalProperties  *node
+	required              []string
+	minProperties         *int
+	maxProperties         *int
+	propertyNames         *node
+	dependentRequired     map[string][]string
+	dependentSchemas      map[string]*node
+	dependenciesKeyword   string // dependencies in drafts before 2019-09
+	unevaluatedProperties *node
+
+	allOf      []*node
+	anyOf      []*node
+	oneOf      []*node
+	not        *node
+	ifSchema   *node
+	thenSchema *node
+	elseSchema *node
+}
+
+type patternNode struct {
+	source string
+	regexp *regexp.Regexp
+	schema *node
+}
+
+// entry is a raw schema found while scanning documents
+type entry struct {
+	raw any
+	// base is base URI used to resolve references inside schema
+	base string
+	// location is canonical location of schema
+	location string
+}
+
+type compiler struct {
+	assertFormat bool
+	idKeyword    string
+	// entries contains schemas by all known locations: json pointers, $id and anchors
+	entries map[string]*entry
+	// resources contains documents and subschemas with $id by URI without fragment
+	resources map[string]any
+	// dynamicAnchors contains schemas with $dynamicAnchor by <base uri>#<anchor> and
+	// resources with $recursiveAnchor by <base uri>#
+	dynamicAnchors map[string]*entry
+	nodes          map[*entry]*node
+}
+
+func newCompiler() *compiler {
+	return &compiler{
+		idKeyword:      "$id",
+		entries:        make(map[string]*entry),
+		resources:      make(map[string]any),
+		dynamicAnchors: make(map[string]*entry),
+		nodes:          make(map[*entry]*node),
+	}
+}
+
+func (c *compiler) compileDocument(doc any) (*node, error) {
+	if obj, ok := doc.(map[string]any); ok {
+		if draft, ok := obj["$schema"].(string); ok && strings.Contains(draft, "draft-04") {
+			c.idKeyword = "id"
+		}
+	}
+	if err := c.addResource("", doc); err != nil {
+		return nil, err
+	}
+	root, err := c.compile(c.entries["#"])
+	if err != nil {
+		return nil, err
+	}
+
+	// Targets of dynamic references are known only while evaluating, so all dynamic anchors are compiled.
+	// Compilation can load new resources with more anchors.
+	for compiled := 0; compiled < len(c.dynamicAnchors); {
+		compiled = len(c.dynamicAnchors)
+		for _, key := range sortedKeys(c.dynamicAnchors) {
+			if _, err = c.compile(c.dynamicAnchors[key]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = c.checkCycles(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// checkCycles fails, if references form cycle of schemas, that apply to the same instance. Evaluation of such
+// cycle never ends.
+func (c *compiler) checkCycles() error {
+	nodes := make([]*node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].location < nodes[j].location })
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[*node]int, len(nodes))
+	var path []string
+	var visit func(n *node) error
+	visit = func(n *node) error {
+		switch states[n] {
+		case visiting:
+			return fmt.Errorf("%w: %s -> %s", ErrReferenceCycle, strings.Join(path, " -> "), n.location)
+		case visited:
+			return nil
+		}
+		states[n] = visiting
+		path = append(path, n.location)
+		for _, next := range n.inPlaceSubschemas() {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[n] = visited
+		return nil
+	}
+	for _, n := range nodes {
+		if err := visit(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inPlaceSubschemas returns subschemas, that apply to the same instance as schema
+func (n *node) inPlaceSubschemas() []*node {
+	subschemas := []*node{n.ref, n.dynamicRef, n.not, n.ifSchema, n.thenSchema, n.elseSchema}
+	subschemas = append(subschemas, n.allOf...)
+	subschemas = append(subschemas, n.anyOf...)
+	subschemas = append(subschemas, n.oneOf...)
+	for _, name := range sortedKeys(n.dependentSchemas) {
+		subschemas = append(subschemas, n.dependentSchemas[name])
+	}
+	return slices.DeleteFunc(subschemas, func(subschema *node) bool { return subschema == nil })
+}
+
+// dynamicAnchorNodes returns compiled schemas of dynamic anchors
+func (c *compiler) dynamicAnchorNodes() map[string]*node {
+	nodes := make(map[string]*node, len(c.dynamicAnchors))
+	for key, e := range c.dynamicAnchors {
+		nodes[key] = c.nodes[e]
+	}
+	return nodes
+}
+
+// addResource registers document available by uri
+func (c *compiler) addResource(uri string, doc any) error {
+	c.resources[uri] = doc
+	return c.scan(doc, uri, "")
+}
+
+// scan registers schema and all its subschemas by their locations
+func (c *compiler) scan(raw any, base, pointer string) error {
+	parentLocation := base + "#" + pointer
+	e := &entry{raw: raw, base: base, location: parentLocation}
+	c.entries[parentLocation] = e
+
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	if id, ok := obj[c.idKeyword].(string); ok && !strings.HasPrefix(id, "#") {
+		resolved, err := resolveURI(base, id)
+		if err != nil {
+			return fmt.Errorf("invalid %s at %s: %w", c.idKeyword, parentLocation, err)
+		}
+		base, _ = splitFragment(resolved)
+		pointer = ""
+		e.base = base
+		e.location = base + "#"
+		c.entries[e.location] = e
+		c.resources[base] = raw
+	}
+
+	for _, anchorKeyword := range []string{"$anchor", "$dynamicAnchor"} {
+		if anchor, ok := obj[anchorKeyword].(string); ok {
+			c.entries[base+"#"+anchor] = e
+		}
+	}
+	if anchor, ok := obj["$dynamicAnchor"].(string); ok {
+		c.dynamicAnchors[base+"#"+anchor] = e
+	}
+	if recursiveAnchor, _ := obj["$recursiveAnchor"].(bool); recursiveAnchor && e.location == base+"#" {
+		c.dynamicAnchors[base+"#"] = e
+	}
+	// Drafts before 2019-09 declare anchors as $id with fragment
+	if id, ok := obj[c.idKeyword].(string); ok && strings.HasPrefix(id, "#") && len(id) > 1 {
+		c.entries[base+id] = e
+	}
+
+	return entity.EachSubschema(obj, func(subschema any, subschemaPointer string) error {
+		return c.scan(subschema, base, pointer+subschemaPointer)
+	})
+}
+
+// resolve finds schema by reference relative to base
+func (c *compiler) resolve(base, ref string) (*entry, error) {
+	resolved, err := resolveURI(base, ref)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := c.entries[resolved]; ok {
+		return e, nil
+	}
+	if !strings.Contains(resolved, "#") {
+		if e, ok := c.entries[resolved+"#"]; ok {
+			return e, nil
+		}
+	}
+
+	// Reference can point into location, that is not a known subschema
+	uri, fragment := splitFragment(resolved)
+	doc, ok := c.resources[uri]
+	if !ok {
+		// Official meta-schemas are available without registration
+		if doc, ok = c.loadMetaSchema(uri); ok {
+			return c.resolve(base, ref)
+		}
+	}
+	if !ok || !strings.HasPrefix(fragment, "/") {
+		return nil, fmt.Errorf("unresolved reference %s", ref)
+	}
+	raw, err := lookupPointer(doc, fragment)
+	if err != nil {
+		return nil, fmt.Errorf("unresolved reference %s: %w", ref, err)
+	}
+	if err = c.scan(raw, uri, fragment); err != nil {
+		return nil, err
+	}
+	return c.entries[uri+"#"+fragment], nil
+}
+
+func (c *compiler) compile(e *entry) (*node, error) {
+	if n, ok := c.nodes[e]; ok {
+		return n, nil
+	}
+	n := &node{location: e.location}
+	if e.location == e.base+"#" {
+		n.resource = &e.base
+	}
+	c.nodes[e] = n
+
+	switch raw := e.raw.(type) {
+	case bool:
+		n.always = &raw
+		return n, nil
+	case map[string]any:
+		if err := c.compileKeywords(e, n, raw); err != nil {
+			return nil, err
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("schema at %s must be object or boolean, got %T", e.location, e.raw)
+	}
+}
+
+// child compiles subschema by its keyword path
+func (c *compiler) child(e *entry, path ...string) (*node, error) {
+	tokens := make([]string, len(path))
+	for i, token := range path {
+		tokens[i] = escapePointer(token)
+	}
+	location := e.location + "/" + strings.Join(tokens, "/")
+	childEntry, ok := c.entries[location]
+	if !ok {
+		return nil, fmt.Errorf("schema at %s not found", location)
+	}
+	return c.compile(childEntry)
+}
+
+func (c *compiler) compileKeywords(e *entry, n *node, obj map[string]any) error {
+	var err error
+	fail := func(keyword string, err error) error {
+		return fmt.Errorf("invalid %s at %s: %w", keyword, e.location, err)
+	}
+
+	// References
+	if ref, ok := obj["$ref"].(string); ok {
+		target, err := c.resolve(e.base, ref)
+		if err != nil {
+			return fail("$ref", err)
+		}
+		if n.ref, err = c.compile(target); err != nil {
+			return err
+		}
+	}
+	for _, keyword := range []string{"$dynamicRef", "$recursiveRef"} {
+		if ref, ok := obj[keyword].(string); ok {
+			// Dynamic references are resolved statically, that is the same as $ref for not extended schemas
+			target, err := c.resolve(e.base, ref)
+			if err != nil {
+				return fail(keyword, err)
+			}
+			if n.dynamicRef, err = c.compile(target); err != nil {
+				return err
+			}
+			n.dynamicRefKeyword = keyword
+			n.dynamicRefAnchor = dynamicRefAnchor(keyword, ref, target)
+		}
+	}
+
+	// Any instance type
+	switch types := obj["type"].(type) {
+	case string:
+		n.types = []string{types}
+	case []any:
+		for _, typ := range types {
+			if typeName, ok := typ.(string); ok {
+				n.types = append(n.types, typeName)
+			}
+		}
+	}
+	if enum, ok := obj["enum"].([]any); ok {
+		n.enum, n.hasEnum = enum, true
+	}
+	if constant, ok := obj["const"]; ok {
+		n.constant, n.hasConst = constant, true
+	}
+
+	// Numeric
+	if n.multipleOf, err = ratKeyword(obj, "multipleOf"); err != nil {
+		return fail("multipleOf", err)
+	}
+	if n.maximum, err = ratKeyword(obj, "maximum"); err != nil {
+		return fail("maximum", err)
+	}
+	if n.minimum, err = ratKeyword(obj, "minimum"); err != nil {
+		return fail("minimum", err)
+	}
+	if exclusive, ok := obj["exclusiveMaximum"].(bool); ok {
+		n.exclusiveMaximumBool = exclusive
+	} else if n.exclusiveMaximum, err = ratKeyword(obj, "exclusiveMaximum"); err != nil {
+		return fail("exclusiveMaximum", err)
+	}
+	if exclusive, ok := obj["exclusiveMinimum"].(bool); ok {
+		n.exclusiveMinimumBool = exclusive
+	} else if n.exclusiveMinimum, err = ratKeyword(obj, "exclusiveMinimum"); err != nil {
+		return fail("exclusiveMinimum", err)
+	}
+
+	// String
+	if n.minLength, err = intKeyword(obj, "minLength"); err != nil {
+		return fail("minLength", err)
+	}
+	if n.maxLength, err = intKeyword(obj, "maxLength"); err != nil {
+		return fail("maxLength", err)
+	}
+	if pattern, ok := obj["pattern"].(string); ok {
+		if n.pattern, err = regexp.Compile(pattern); err != nil {
+			return fail("pattern", err)
+		}
+	}
+	if format, ok := obj["format"].(string); ok {
+		n.format = format
+	}
+
+	// Array
+	if prefixItems, ok := obj["prefixItems"].([]any); ok {
+		n.prefixKeyword = "prefixItems"
+		for i := range prefixItems {
+			prefixItem, err := c.child(e, "prefixItems", strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+			n.prefixItems = append(n.prefixItems, prefixItem)
+		}
+	}
+	switch items := obj["items"].(type) {
+	case []any:
+		n.prefixKeyword = "items"
+		for i := range items {
+			prefixItem, err := c.child(e, "items", strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+			n.prefixItems = append(n.prefixItems, prefixItem)
+		}
+		if isSchema(obj["additionalItems"]) {
+			n.itemsKeyword = "additionalItems"
+			if n.items, err = c.child(e, "additionalItems"); err != nil {
+				return err
+			}
+		}
+	case map[string]any, bool:
+		n.itemsKeyword = "items"
+		if n.items, err = c.child(e, "items"); err != nil {
+			return err
+		}
+	}
+	if isSchema(obj["contains"]) {
+		if n.contains, err = c.child(e, "contains"); err != nil {
+			return err
+		}
+	}
+	if n.minContains, err = intKeyword(obj, "minContains"); err != nil {
+		return fail("minContains", err)
+	}
+	if n.maxContains, err = intKeyword(obj, "maxContains"); err != nil {
+		return fail("maxContains", err)
+	}
+	if n.minItems, err = intKeyword(obj, "minItems"); err != nil {
+		return fail("minItems", err)
+	}
+	if n.maxItems, err = intKeyword(obj, "maxItems"); err != nil {
+		return fail("maxItems", err)
+	}
+	n.uniqueItems, _ = obj["uniqueItems"].(bool)
+	if isSchema(obj["unevaluatedItems"]) {
+		if n.unevaluatedItems, err = c.child(e, "unevaluatedItems"); err != nil {
+			return err
+		}
+	}
+
+	// Object
+	if properties, ok := obj["properties"].(map[string]any); ok {
+		n.properties = make(map[string]*node, len(properties))
+		for name := range properties {
+			if n.properties[name], err = c.child(e, "properties", name); err != nil {
+				return err
+			}
+		}
+	}
+	if patternProperties, ok := obj["patternProperties"].(map[string]any); ok {
+		for _, pattern := range sortedKeys(patternProperties) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fail("patternProperties", err)
+			}
+			schema, err := c.child(e, "patternProperties", pattern)
+			if err != nil {
+				return err
+			}
+			n.patternProperties = append(n.patternProperties, &patternNode{source: pattern, regexp: re, schema: schema})
+		}
+	}
+	if isSchema(obj["additionalProperties"]) {
+		if n.additionalProperties, err = c.child(e, "additionalProperties"); err != nil {
+			return err
+		}
+	}
+	if n.required, err = stringsKeyword(obj, "required"); err != nil {
+		return fail("required", err)
+	}
+	if n.minProperties, err = intKeyword(obj, "minProperties"); err != nil {
+		return fail("minProperties", err)
+	}
+	if n.maxProperties, err = intKeyword(obj, "maxProperties"); err != nil {
+		return fail("maxProperties", err)
+	}
+	if isSchema(obj["propertyNames"]) {
+		if n.propertyNames, err = c.child(e, "propertyNames"); err != nil {
+			return err
+		}
+	}
+	if err = c.compileDependencies(e, n, obj); err != nil {
+		return err
+	}
+	if isSchema(obj["unevaluatedProperties"]) {
+		if n.unevaluatedProperties, err = c.child(e, "unevaluatedProperties"); err != nil {
+			return err
+		}
+	}
+
+	// Applicators
+	for keyword, target := range map[string]*[]*node{"allOf": &n.allOf, "anyOf": &n.anyOf, "oneOf": &n.oneOf} {
+		schemas, ok := obj[keyword].([]any)
+		if !ok {
+			continue
+		}
+		for i := range schemas {
+			schema, err := c.child(e, keyword, strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+			*target = append(*target, schema)
+		}
+	}
+	for keyword, target := range map[string]**node{"not": &n.not, "if": &n.ifSchema, "then": &n.thenSchema, "else": &n.elseSchema} {
+		if isSchema(obj[keyword]) {
+			if *target, err = c.child(e, keyword); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *compiler) compileDependencies(e *entry, n *node, obj map[string]any) error {
+	if dependentRequired, ok := obj["dependentRequired"].(map[string]any); ok {
+		n.dependentRequired = make(map[string][]string, len(dependentRequired))
+		for name := range dependentRequired {
+			required, err := stringsKeyword(dependentRequired, name)
+			if err != nil {
+				return fmt.Errorf("invalid dependentRequired at %s: %w", e.location, err)
+			}
+			n.dependentRequired[name] = required
+		}
+	}
+	if dependentSchemas, ok := obj["dependentSchemas"].(map[string]any); ok {
+		n.dependentSchemas = make(map[string]*node, len(dependentSchemas))
+		for name := range dependentSchemas {
+			schema, err := c.child(e, "dependentSchemas", name)
+			if err != nil {
+				return err
+			}
+			n.dependentSchemas[name] = schema
+		}
+	}
+
+	// Drafts before 2019-09 combine both keywords in dependencies
+	dependencies, ok := obj["dependencies"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	n.dependenciesKeyword = "dependencies"
+	for name, dependency := range dependencies {
+		if isSchema(dependency) {
+			schema, err := c.child(e, "dependencies", name)
+			if err != nil {
+				return err
+			}
+			if n.dependentSchemas == nil {
+				n.dependentSchemas = make(map[string]*node)
+			}
+			n.dependentSchemas[name] = schema
+			continue
+		}
+		required, err := stringsKeyword(dependencies, name)
+		if err != nil {
+			return fmt.Errorf("invalid dependencies at %s: %w", e.location, err)
+		}
+		if n.dependentRequired == nil {
+			n.dependentRequired = make(map[string][]string)
+		}
+		n.dependentRequired[name] = required
+	}
+	return nil
+}
+
+// dynamicRefAnchor returns anchor, that is resolved in dynamic scope. Dynamic reference behaves like $ref,
+// if its initial target has no matching dynamic anchor.
+func dynamicRefAnchor(keyword, ref string, target *entry) *string {
+	obj, ok := target.raw.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if keyword == "$recursiveRef" {
+		if recursiveAnchor, _ := obj["$recursiveAnchor"].(bool); recursiveAnchor {
+			anchor := ""
+			return &anchor
+		}
+		return nil
+	}
+	_, fragment := splitFragment(ref)
+	if anchor, _ := obj["$dynamicAnchor"].(string); anchor != "" && anchor == fragment {
+		return &anchor
+	}
+	return nil
+}
+
+func isSchema(value any) bool {
+	switch value.(type) {
+	case map[string]any, bool:
+		return true
+	}
+	return false
+}
+
+func ratKeyword(obj map[string]any, keyword string) (*big.Rat, error) {
+	value, ok := obj[keyword]
+	if !ok {
+		return nil, nil
+	}
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("expected number, got %T", value)
+	}
+	rat, ok := new(big.Rat).SetString(number.String())
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", number)
+	}
+	return rat, nil
+}
+
+func intKeyword(obj map[string]any, keyword string) (*int, error) {
+	rat, err := ratKeyword(obj, keyword)
+	if err != nil || rat == nil {
+		return nil, err
+	}
+	if !rat.IsInt() || !rat.Num().IsInt64() {
+		return nil, fmt.Errorf("expected integer, got %s", rat.RatString())
+	}
+	value := int(rat.Num().Int64())
+	return &value, nil
+}
+
+func stringsKeyword(obj map[string]any, keyword string) ([]string, error) {
+	value, ok := obj[keyword]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected array of strings, got %T", value)
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		if result[i], ok = value.(string); !ok {
+			return nil, fmt.Errorf("expected array of strings, got %T item", value)
+		}
+	}
+	return result, nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolveURI resolves reference relative to base URI. Result has form <uri>#<unescaped fragment>.
+func resolveURI(base, ref string) (string, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	if base != "" {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		refURL = baseURL.ResolveReference(refURL)
+	}
+	fragment := refURL.Fragment
+	hasFragment := strings.Contains(ref, "#")
+	refURL.Fragment, refURL.RawFragment = "", ""
+	if !hasFragment {
+		return refURL.String(), nil
+	}
+	return refURL.String() + "#" + fragment, nil
+}
+
+func splitFragment(uri string) (string, string) {
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		return uri[:i], uri[i+1:]
+	}
+	return uri, ""
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// lookupPointer finds value in document by JSON Pointer
+func lookupPointer(doc any, pointer string) (any, error) {
+	if pointer == "" {
+		return doc, nil
+	}
+	current := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescapePointer(token)
+		switch value := current.(type) {
+		case map[string]any:
+			next, ok := value[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			current = value[index]
+		default:
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+	}
+	return current, nil
+}
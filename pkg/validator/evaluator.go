@@ -0,0 +1,758 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// result is outcome of schema or keyword evaluation. Results of subschemas and keywords are children,
+// so the whole evaluation is a tree.
+type result struct {
+	valid                   bool
+	keyword                 string
+	keywordLocation         string
+	absoluteKeywordLocation string
+	instanceLocation        string
+	message                 string
+	children                []*result
+}
+
+// annotations collected by successfully evaluated keywords and used by unevaluated keywords
+type annotations struct {
+	// items is count of leading array items evaluated by prefixItems
+	items         int
+	allItems      bool
+	containsItems map[int]bool
+	properties    map[string]bool
+}
+
+func (a *annotations) merge(other *annotations) {
+	if other == nil {
+		return
+	}
+	if other.items > a.items {
+		a.items = other.items
+	}
+	a.allItems = a.allItems || other.allItems
+	for index := range other.containsItems {
+		if a.containsItems == nil {
+			a.containsItems = make(map[int]bool)
+		}
+		a.containsItems[index] = true
+	}
+	for name := range other.properties {
+		a.addProperty(name)
+	}
+}
+
+func (a *annotations) addProperty(name string) {
+	if a.properties == nil {
+		a.properties = make(map[string]bool)
+	}
+	a.properties[name] = true
+}
+
+// validationErrors returns errors of invalid keywords. Keywords with own message describe failure of
+// their subschemas (e.g. anyOf), so errors of subschemas are not included for them.
+func (r *result) validationErrors() ValidationErrors {
+	var errs ValidationErrors
+	var collect func(r *result)
+	collect = func(r *result) {
+		if r.valid {
+			return
+		}
+		if r.message != "" {
+			errs = append(errs, &ValidationError{
+				Keyword:                 r.keyword,
+				KeywordLocation:         r.keywordLocation,
+				AbsoluteKeywordLocation: absoluteLocation(r.absoluteKeywordLocation),
+				InstanceLocation:        r.instanceLocation,
+				Message:                 r.message,
+			})
+			return
+		}
+		for _, child := range r.children {
+			collect(child)
+		}
+	}
+	collect(r)
+	return errs
+}
+
+type evaluator struct {
+	assertFormat   bool
+	formatCheckers map[string]FormatChecker
+	dynamicAnchors map[string]*node
+	// scope contains base URIs of entered schema resources, the outermost is first
+	scope []string
+}
+
+// keywordUnit creates result for keyword of schema
+func keywordUnit(n *node, keyword, keywordLocation, instanceLocation string) *result {
+	return &result{
+		valid:                   true,
+		keyword:                 keyword,
+		keywordLocation:         keywordLocation + "/" + keyword,
+		absoluteKeywordLocation: n.location + "/" + keyword,
+		instanceLocation:        instanceLocation,
+	}
+}
+
+func (r *result) fail(format string, args ...any) {
+	r.valid = false
+	r.message = fmt.Sprintf(format, args...)
+}
+
+// addChild adds result of subschema and invalidates keyword, if subschema is invalid
+func (r *result) addChild(child *result) {
+	r.children = append(r.children, child)
+	if !child.valid {
+		r.valid = false
+	}
+}
+
+func (e *evaluator) evaluate(n *node, instance any, keywordLocation, instanceLocation string) *result {
+	res, _ := e.evaluateWithAnnotations(n, instance, keywordLocation, instanceLocation)
+	return res
+}
+
+func (e *evaluator) evaluateWithAnnotations(n *node, instance any, keywordLocation,
+	instanceLocation string) (*result, *annotations) {
+	res := &result{
+		valid:                   true,
+		keywordLocation:         keywordLocation,
+		absoluteKeywordLocation: n.location,
+		instanceLocation:        instanceLocation,
+	}
+	ann := &annotations{}
+
+	if n.resource != nil {
+		e.scope = append(e.scope, *n.resource)
+		defer func() { e.scope = e.scope[:len(e.scope)-1] }()
+	}
+
+	if n.always != nil {
+		if !*n.always {
+			res.fail("not allowed by false schema")
+			return res, nil
+		}
+		return res, ann
+	}
+
+	add := func(unit *result) {
+		if unit != nil {
+			res.children = append(res.children, unit)
+			if !unit.valid {
+				res.valid = false
+			}
+		}
+	}
+
+	// References
+	if n.ref != nil {
+		add(e.evaluateReference(n, n.ref, "$ref", instance, keywordLocation, instanceLocation, ann))
+	}
+	if n.dynamicRef != nil {
+		add(e.evaluateReference(n, e.dynamicTarget(n), n.dynamicRefKeyword, instance, keywordLocation, instanceLocation, ann))
+	}
+
+	// Any instance type
+	add(e.evaluateType(n, instance, keywordLocation, instanceLocation))
+	add(e.evaluateEnum(n, instance, keywordLocation, instanceLocation))
+	add(e.evaluateConst(n, instance, keywordLocation, instanceLocation))
+
+	// Applicators
+	for _, unit := range e.evaluateApplicators(n, instance, keywordLocation, instanceLocation, ann) {
+		add(unit)
+	}
+
+	switch value := instance.(type) {
+	case json.Number:
+		for _, unit := range e.evaluateNumber(n, value, keywordLocation, instanceLocation) {
+			add(unit)
+		}
+	case string:
+		for _, unit := range e.evaluateString(n, value, keywordLocation, instanceLocation) {
+			add(unit)
+		}
+	case []any:
+		for _, unit := range e.evaluateArray(n, value, keywordLocation, instanceLocation, ann) {
+			add(unit)
+		}
+	case map[string]any:
+		for _, unit := range e.evaluateObject(n, value, keywordLocation, instanceLocation, ann) {
+			add(unit)
+		}
+	}
+
+	if !res.valid {
+		return res, nil
+	}
+	return res, ann
+}
+
+func (e *evaluator) evaluateReference(n, target *node, keyword string, instance any, keywordLocation,
+	instanceLocation string, ann *annotations) *result {
+	unit := keywordUnit(n, keyword, keywordLocation, instanceLocation)
+	child, childAnn := e.evaluateWithAnnotations(target, instance, unit.keywordLocation, instanceLocation)
+	unit.addChild(child)
+	ann.merge(childAnn)
+	return unit
+}
+
+// dynamicTarget resolves dynamic reference to the outermost schema resource in dynamic scope,
+// that has the dynamic anchor
+func (e *evaluator) dynamicTarget(n *node) *node {
+	if n.dynamicRefAnchor == nil {
+		return n.dynamicRef
+	}
+	for _, base := range e.scope {
+		if target, ok := e.dynamicAnchors[base+"#"+*n.dynamicRefAnchor]; ok {
+			return target
+		}
+	}
+	return n.dynamicRef
+}
+
+func (e *evaluator) evaluateType(n *node, instance any, keywordLocation, instanceLocation string) *result {
+	if len(n.types) == 0 {
+		return nil
+	}
+	unit := keywordUnit(n, "type", keywordLocation, instanceLocation)
+	actual := instanceType(instance)
+	for _, typ := range n.types {
+		if typ == actual || (typ == "number" && actual == "integer") {
+			return unit
+		}
+	}
+	unit.fail("expected %s, but got %s", strings.Join(n.types, " or "), actual)
+	return unit
+}
+
+func (e *evaluator) evaluateEnum(n *node, instance any, keywordLocation, instanceLocation string) *result {
+	if !n.hasEnum {
+		return nil
+	}
+	unit := keywordUnit(n, "enum", keywordLocation, instanceLocation)
+	for _, value := range n.enum {
+		if equalJSON(value, instance) {
+			return unit
+		}
+	}
+	unit.fail("value must be one of %s", jsonString(n.enum))
+	return unit
+}
+
+func (e *evaluator) evaluateConst(n *node, instance any, keywordLocation, instanceLocation string) *result {
+	if !n.hasConst {
+		return nil
+	}
+	unit := keywordUnit(n, "const", keywordLocation, instanceLocation)
+	if !equalJSON(n.constant, instance) {
+		unit.fail("value must be %s", jsonString(n.constant))
+	}
+	return unit
+}
+
+func (e *evaluator) evaluateApplicators(n *node, instance any, keywordLocation, instanceLocation string,
+	ann *annotations) []*result {
+	var units []*result
+
+	if len(n.allOf) > 0 {
+		unit := keywordUnit(n, "allOf", keywordLocation, instanceLocation)
+		for i, schema := range n.allOf {
+			child, childAnn := e.evaluateWithAnnotations(schema, instance, unit.keywordLocation+"/"+strconv.Itoa(i), instanceLocation)
+			unit.addChild(child)
+			ann.merge(childAnn)
+		}
+		units = append(units, unit)
+	}
+
+	if len(n.anyOf) > 0 {
+		unit := keywordUnit(n, "anyOf", keywordLocation, instanceLocation)
+		matched := false
+		for i, schema := range n.anyOf {
+			child, childAnn := e.evaluateWithAnnotations(schema, instance, unit.keywordLocation+"/"+strconv.Itoa(i), instanceLocation)
+			unit.children = append(unit.children, child)
+			if child.valid {
+				matched = true
+				ann.merge(childAnn)
+			}
+		}
+		if !matched {
+			unit.fail("value does not match any of %d schemas", len(n.anyOf))
+		}
+		units = append(units, unit)
+	}
+
+	if len(n.oneOf) > 0 {
+		unit := keywordUnit(n, "oneOf", keywordLocation, instanceLocation)
+		var matched []string
+		var matchedAnn *annotations
+		for i, schema := range n.oneOf {
+			child, childAnn := e.evaluateWithAnnotations(schema, instance, unit.keywordLocation+"/"+strconv.Itoa(i), instanceLocation)
+			unit.children = append(unit.children, child)
+			if child.valid {
+				matched = append(matched, strconv.Itoa(i))
+				matchedAnn = childAnn
+			}
+		}
+		switch len(matched) {
+		case 0:
+			unit.fail("value does not match any of %d schemas", len(n.oneOf))
+		case 1:
+			ann.merge(matchedAnn)
+		default:
+			unit.fail("value matches more than one schema: %s", strings.Join(matched, ", "))
+		}
+		units = append(units, unit)
+	}
+
+	if n.not != nil {
+		unit := keywordUnit(n, "not", keywordLocation, instanceLocation)
+		child := e.evaluate(n.not, instance, unit.keywordLocation, instanceLocation)
+		unit.children = append(unit.children, child)
+		if child.valid {
+			unit.fail("value must not match schema")
+		}
+		units = append(units, unit)
+	}
+
+	if n.ifSchema != nil {
+		ifUnit := keywordUnit(n, "if", keywordLocation, instanceLocation)
+		ifResult, ifAnn := e.evaluateWithAnnotations(n.ifSchema, instance, ifUnit.keywordLocation, instanceLocation)
+		// if never fails itself, it only selects then or else
+		ifUnit.children = append(ifUnit.children, ifResult)
+		units = append(units, ifUnit)
+
+		keyword, branch := "else", n.elseSchema
+		if ifResult.valid {
+			ann.merge(ifAnn)
+			keyword, branch = "then", n.thenSchema
+		}
+		if branch != nil {
+			unit := keywordUnit(n, keyword, keywordLocation, instanceLocation)
+			child, childAnn := e.evaluateWithAnnotations(branch, instance, unit.keywordLocation, instanceLocation)
+			unit.addChild(child)
+			ann.merge(childAnn)
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
+func (e *evaluator) evaluateNumber(n *node, value json.Number, keywordLocation, instanceLocation string) []*result {
+	number, ok := new(big.Rat).SetString(value.String())
+	if !ok {
+		return nil
+	}
+	var units []*result
+	check := func(keyword string, valid bool, format string, limit *big.Rat) {
+		unit := keywordUnit(n, keyword, keywordLocation, instanceLocation)
+		if !valid {
+			unit.fail(format, value, limit.RatString())
+		}
+		units = append(units, unit)
+	}
+
+	if n.multipleOf != nil && n.multipleOf.Sign() != 0 {
+		quotient := new(big.Rat).Quo(number, n.multipleOf)
+		check("multipleOf", quotient.IsInt(), "%s is not a multiple of %s", n.multipleOf)
+	}
+	if n.maximum != nil {
+		if n.exclusiveMaximumBool {
+			check("maximum", number.Cmp(n.maximum) < 0, "%s must be < %s", n.maximum)
+		} else {
+			check("maximum", number.Cmp(n.maximum) <= 0, "%s must be <= %s", n.maximum)
+		}
+	}
+	if n.exclusiveMaximum != nil {
+		check("exclusiveMaximum", number.Cmp(n.exclusiveMaximum) < 0, "%s must be < %s", n.exclusiveMaximum)
+	}
+	if n.minimum != nil {
+		if n.exclusiveMinimumBool {
+			check("minimum", number.Cmp(n.minimum) > 0, "%s must be > %s", n.minimum)
+		} else {
+			check("minimum", number.Cmp(n.minimum) >= 0, "%s must be >= %s", n.minimum)
+		}
+	}
+	if n.exclusiveMinimum != nil {
+		check("exclusiveMinimum", number.Cmp(n.exclusiveMinimum) > 0, "%s must be > %s", n.exclusiveMinimum)
+	}
+	return units
+}
+
+func (e *evaluator) evaluateString(n *node, value string, keywordLocation, instanceLocation string) []*result {
+	var units []*result
+	length := utf8.RuneCountInString(value)
+	if n.minLength != nil {
+		unit := keywordUnit(n, "minLength", keywordLocation, instanceLocation)
+		if length < *n.minLength {
+			unit.fail("length must be >= %d, but got %d", *n.minLength, length)
+		}
+		units = append(units, unit)
+	}
+	if n.maxLength != nil {
+		unit := keywordUnit(n, "maxLength", keywordLocation, instanceLocation)
+		if length > *n.maxLength {
+			unit.fail("length must be <= %d, but got %d", *n.maxLength, length)
+		}
+		units = append(units, unit)
+	}
+	if n.pattern != nil {
+		unit := keywordUnit(n, "pattern", keywordLocation, instanceLocation)
+		if !n.pattern.MatchString(value) {
+			unit.fail("%q does not match pattern %q", value, n.pattern.String())
+		}
+		units = append(units, unit)
+	}
+	if n.format != "" && e.assertFormat {
+		if checker, ok := e.formatCheckers[n.format]; ok {
+			unit := keywordUnit(n, "format", keywordLocation, instanceLocation)
+			if !checker(value) {
+				unit.fail("%q is not valid %s", value, n.format)
+			}
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
+func (e *evaluator) evaluateArray(n *node, value []any, keywordLocation, instanceLocation string,
+	ann *annotations) []*result {
+	var units []*result
+
+	if len(n.prefixItems) > 0 {
+		unit := keywordUnit(n, n.prefixKeyword, keywordLocation, instanceLocation)
+		for i, schema := range n.prefixItems {
+			if i >= len(value) {
+				break
+			}
+			unit.addChild(e.evaluate(schema, value[i], unit.keywordLocation+"/"+strconv.Itoa(i), instanceLocation+"/"+strconv.Itoa(i)))
+		}
+		if unit.valid {
+			ann.items = max(ann.items, min(len(n.prefixItems), len(value)))
+		}
+		units = append(units, unit)
+	}
+
+	if n.items != nil {
+		unit := keywordUnit(n, n.itemsKeyword, keywordLocation, instanceLocation)
+		for i := len(n.prefixItems); i < len(value); i++ {
+			unit.addChild(e.evaluate(n.items, value[i], unit.keywordLocation, instanceLocation+"/"+strconv.Itoa(i)))
+		}
+		if unit.valid {
+			ann.allItems = true
+		}
+		units = append(units, unit)
+	}
+
+	if n.contains != nil {
+		unit := keywordUnit(n, "contains", keywordLocation, instanceLocation)
+		var matched []int
+		for i, item := range value {
+			child := e.evaluate(n.contains, item, unit.keywordLocation, instanceLocation+"/"+strconv.Itoa(i))
+			unit.children = append(unit.children, child)
+			if child.valid {
+				matched = append(matched, i)
+			}
+		}
+		minContains := 1
+		if n.minContains != nil {
+			minContains = *n.minContains
+		}
+		switch {
+		case len(matched) < minContains:
+			unit.fail("array must contain at least %d matching items, but contains %d", minContains, len(matched))
+		case n.maxContains != nil && len(matched) > *n.maxContains:
+			unit.fail("array must contain at most %d matching items, but contains %d", *n.maxContains, len(matched))
+		default:
+			for _, index := range matched {
+				if ann.containsItems == nil {
+					ann.containsItems = make(map[int]bool)
+				}
+				ann.containsItems[index] = true
+			}
+		}
+		units = append(units, unit)
+	}
+
+	if n.minItems != nil {
+		unit := keywordUnit(n, "minItems", keywordLocation, instanceLocation)
+		if len(value) < *n.minItems {
+			unit.fail("array must have at least %d items, but has %d", *n.minItems, len(value))
+		}
+		units = append(units, unit)
+	}
+	if n.maxItems != nil {
+		unit := keywordUnit(n, "maxItems", keywordLocation, instanceLocation)
+		if len(value) > *n.maxItems {
+			unit.fail("array must have at most %d items, but has %d", *n.maxItems, len(value))
+		}
+		units = append(units, unit)
+	}
+	if n.uniqueItems {
+		unit := keywordUnit(n, "uniqueItems", keywordLocation, instanceLocation)
+	unique:
+		for i := range value {
+			for j := i + 1; j < len(value); j++ {
+				if equalJSON(value[i], value[j]) {
+					unit.fail("items at %d and %d are equal", i, j)
+					break unique
+				}
+			}
+		}
+		units = append(units, unit)
+	}
+
+	if n.unevaluatedItems != nil {
+		unit := keywordUnit(n, "unevaluatedItems", keywordLocation, instanceLocation)
+		if !ann.allItems {
+			for i := ann.items; i < len(value); i++ {
+				if ann.containsItems[i] {
+					continue
+				}
+				unit.addChild(e.evaluate(n.unevaluatedItems, value[i], unit.keywordLocation, instanceLocation+"/"+strconv.Itoa(i)))
+			}
+			if unit.valid {
+				ann.allItems = true
+			}
+		}
+		units = append(units, unit)
+	}
+	return units
+}
+
+func (e *evaluator) evaluateObject(n *node, value map[string]any, keywordLocation, instanceLocation string,
+	ann *annotations) []*result {
+	var units []*result
+	names := sortedKeys(value)
+
+	if n.properties != nil {
+		unit := keywordUnit(n, "properties", keywordLocation, instanceLocation)
+		for _, name := range names {
+			schema, ok := n.properties[name]
+			if !ok {
+				continue
+			}
+			unit.addChild(e.evaluate(schema, value[name], unit.keywordLocation+"/"+escapePointer(name), instanceLocation+"/"+escapePointer(name)))
+			ann.addProperty(name)
+		}
+		units = append(units, unit)
+	}
+
+	var patternMatched map[string]bool
+	if len(n.patternProperties) > 0 {
+		patternMatched = make(map[string]bool)
+		unit := keywordUnit(n, "patternProperties", keywordLocation, instanceLocation)
+		for _, name := range names {
+			for _, pattern := range n.patternProperties {
+				if !pattern.regexp.MatchString(name) {
+					continue
+				}
+				unit.addChild(e.evaluate(pattern.schema, value[name], unit.keywordLocation+"/"+escapePointer(pattern.source), instanceLocation+"/"+escapePointer(name)))
+				patternMatched[name] = true
+				ann.addProperty(name)
+			}
+		}
+		units = append(units, unit)
+	}
+
+	if n.additionalProperties != nil {
+		unit := keywordUnit(n, "additionalProperties", keywordLocation, instanceLocation)
+		var notAllowed []string
+		for _, name := range names {
+			if _, ok := n.properties[name]; ok || patternMatched[name] {
+				continue
+			}
+			child := e.evaluate(n.additionalProperties, value[name], unit.keywordLocation, instanceLocation+"/"+escapePointer(name))
+			if n.additionalProperties.always != nil && !*n.additionalProperties.always {
+				notAllowed = append(notAllowed, name)
+				continue
+			}
+			unit.addChild(child)
+			ann.addProperty(name)
+		}
+		if len(notAllowed) > 0 {
+			unit.fail("additional properties %s are not allowed", quoteAll(notAllowed))
+		}
+		units = append(units, unit)
+	}
+
+	if len(n.required) > 0 {
+		unit := keywordUnit(n, "required", keywordLocation, instanceLocation)
+		if missing := missingProperties(value, n.required); len(missing) > 0 {
+			unit.fail("missing properties %s", quoteAll(missing))
+		}
+		units = append(units, unit)
+	}
+	if n.minProperties != nil {
+		unit := keywordUnit(n, "minProperties", keywordLocation, instanceLocation)
+		if len(value) < *n.minProperties {
+			unit.fail("object must have at least %d properties, but has %d", *n.minProperties, len(value))
+		}
+		units = append(units, unit)
+	}
+	if n.maxProperties != nil {
+		unit := keywordUnit(n, "maxProperties", keywordLocation, instanceLocation)
+		if len(value) > *n.maxProperties {
+			unit.fail("object must have at most %d properties, but has %d", *n.maxProperties, len(value))
+		}
+		units = append(units, unit)
+	}
+	if n.propertyNames != nil {
+		unit := keywordUnit(n, "propertyNames", keywordLocation, instanceLocation)
+		for _, name := range names {
+			unit.addChild(e.evaluate(n.propertyNames, name, unit.keywordLocation, instanceLocation+"/"+escapePointer(name)))
+		}
+		units = append(units, unit)
+	}
+
+	if len(n.dependentRequired) > 0 {
+		keyword := "dependentRequired"
+		if n.dependenciesKeyword != "" {
+			keyword = n.dependenciesKeyword
+		}
+		unit := keywordUnit(n, keyword, keywordLocation, instanceLocation)
+		for _, name := range sortedKeys(n.dependentRequired) {
+			if _, ok := value[name]; !ok {
+				continue
+			}
+			if missing := missingProperties(value, n.dependentRequired[name]); len(missing) > 0 {
+				unit.fail("properties %s are required, when %q is present", quoteAll(missing), name)
+				break
+			}
+		}
+		units = append(units, unit)
+	}
+	if len(n.dependentSchemas) > 0 {
+		keyword := "dependentSchemas"
+		if n.dependenciesKeyword != "" {
+			keyword = n.dependenciesKeyword
+		}
+		unit := keywordUnit(n, keyword, keywordLocation, instanceLocation)
+		for _, name := range sortedKeys(n.dependentSchemas) {
+			if _, ok := value[name]; !ok {
+				continue
+			}
+			child, childAnn := e.evaluateWithAnnotations(n.dependentSchemas[name], value, unit.keywordLocation+"/"+escapePointer(name), instanceLocation)
+			unit.addChild(child)
+			ann.merge(childAnn)
+		}
+		units = append(units, unit)
+	}
+
+	if n.unevaluatedProperties != nil {
+		unit := keywordUnit(n, "unevaluatedProperties", keywordLocation, instanceLocation)
+		var notAllowed []string
+		for _, name := range names {
+			if ann.properties[name] {
+				continue
+			}
+			if n.unevaluatedProperties.always != nil && !*n.unevaluatedProperties.always {
+				notAllowed = append(notAllowed, name)
+				continue
+			}
+			unit.addChild(e.evaluate(n.unevaluatedProperties, value[name], unit.keywordLocation, instanceLocation+"/"+escapePointer(name)))
+			ann.addProperty(name)
+		}
+		if len(notAllowed) > 0 {
+			unit.fail("unevaluated properties %s are not allowed", quoteAll(notAllowed))
+		}
+		units = append(units, unit)
+	}
+	return units
+}
+
+// instanceType returns JSON Schema type of decoded JSON value
+func instanceType(instance any) string {
+	switch value := instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if number, ok := new(big.Rat).SetString(value.String()); ok && number.IsInt() {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+// equalJSON compares decoded JSON values, numbers are equal by value, e.g. 1 and 1.0
+func equalJSON(a, b any) bool {
+	switch aValue := a.(type) {
+	case json.Number:
+		bValue, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		aRat, aOk := new(big.Rat).SetString(aValue.String())
+		bRat, bOk := new(big.Rat).SetString(bValue.String())
+		return aOk && bOk && aRat.Cmp(bRat) == 0
+	case []any:
+		bValue, ok := b.([]any)
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for i := range aValue {
+			if !equalJSON(aValue[i], bValue[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bValue, ok := b.(map[string]any)
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+		for key, item := range aValue {
+			other, ok := bValue[key]
+			if !ok || !equalJSON(item, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func missingProperties(value map[string]any, required []string) []string {
+	var missing []string
+	for _, name := range required {
+		if _, ok := value[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func quoteAll(values []string) string {
+	sort.Strings(values)
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func jsonString(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
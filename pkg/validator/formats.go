@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FormatChecker returns true, if string value has the format
+type FormatChecker func(value string) bool
+
+// formatCheckers are formats asserted with WithFormatAssertion option. Unknown formats are ignored.
+var formatCheckers = map[string]FormatChecker{
+	"date-time": isDateTime,
+	"date":      isDate,
+	"time":      isTime,
+	"email":     isEmail,
+	"hostname":  isHostname,
+	"ipv4":      isIPv4,
+	"ipv6":      isIPv6,
+	"uri":       isURI,
+	"uuid":      isUUID,
+	"regex":     isRegex,
+}
+
+func isDateTime(value string) bool {
+	_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(value))
+	return err == nil
+}
+
+func isDate(value string) bool {
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
+
+func isTime(value string) bool {
+	_, err := time.Parse("15:04:05.999999999Z07:00", strings.ToUpper(value))
+	return err == nil
+}
+
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+func isHostname(value string) bool {
+	if value == "" || len(value) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(value, "."), ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIPv4(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+}
+
+func isIPv6(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && strings.Contains(value, ":")
+}
+
+func isURI(value string) bool {
+	uri, err := url.Parse(value)
+	return err == nil && uri.IsAbs()
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func isUUID(value string) bool {
+	return uuidPattern.MatchString(value)
+}
+
+func isRegex(value string) bool {
+	_, err := regexp.Compile(value)
+	return err == nil
+}
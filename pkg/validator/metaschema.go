@@ -0,0 +1,177 @@
+package validator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// metaSchemaFS contains official meta-schemas by path of their URI, e.g. draft/2020-12/meta/core.json
+//
+//go:embed metaschemas
+var metaSchemaFS embed.FS
+
+// drafts are supported drafts from the oldest to the newest
+var drafts = []entity.DraftVersion{entity.Draft04, entity.Draft06, entity.Draft07, entity.Draft201909, entity.Draft202012}
+
+// draftRange is range of drafts, that support keyword. Empty since means the oldest draft and
+// empty until means the newest draft.
+type draftRange struct {
+	since entity.DraftVersion
+	// until is the first draft without keyword
+	until entity.DraftVersion
+}
+
+// draftKeywords are keywords, that are not supported by all drafts
+var draftKeywords = map[string]draftRange{
+	"id":                    {until: entity.Draft06},
+	"$id":                   {since: entity.Draft06},
+	"const":                 {since: entity.Draft06},
+	"contains":              {since: entity.Draft06},
+	"propertyNames":         {since: entity.Draft06},
+	"examples":              {since: entity.Draft06},
+	"$comment":              {since: entity.Draft07},
+	"if":                    {since: entity.Draft07},
+	"then":                  {since: entity.Draft07},
+	"else":                  {since: entity.Draft07},
+	"readOnly":              {since: entity.Draft07},
+	"writeOnly":             {since: entity.Draft07},
+	"contentMediaType":      {since: entity.Draft07},
+	"contentEncoding":       {since: entity.Draft07},
+	"dependencies":          {until: entity.Draft201909},
+	"$anchor":               {since: entity.Draft201909},
+	"$defs":                 {since: entity.Draft201909},
+	"$vocabulary":           {since: entity.Draft201909},
+	"$recursiveRef":         {since: entity.Draft201909, until: entity.Draft202012},
+	"$recursiveAnchor":      {since: entity.Draft201909, until: entity.Draft202012},
+	"dependentRequired":     {since: entity.Draft201909},
+	"dependentSchemas":      {since: entity.Draft201909},
+	"unevaluatedItems":      {since: entity.Draft201909},
+	"unevaluatedProperties": {since: entity.Draft201909},
+	"minContains":           {since: entity.Draft201909},
+	"maxContains":           {since: entity.Draft201909},
+	"deprecated":            {since: entity.Draft201909},
+	"contentSchema":         {since: entity.Draft201909},
+	"additionalItems":       {until: entity.Draft202012},
+	"prefixItems":           {since: entity.Draft202012},
+	"$dynamicRef":           {since: entity.Draft202012},
+	"$dynamicAnchor":        {since: entity.Draft202012},
+}
+
+var metaSchemas sync.Map
+
+// MetaSchema returns compiled official meta-schema of draft
+func MetaSchema(draft entity.DraftVersion) (*Schema, error) {
+	if schema, ok := metaSchemas.Load(draft); ok {
+		return schema.(*Schema), nil
+	}
+	data, ok := metaSchemaFile(string(draft))
+	if !ok {
+		return nil, fmt.Errorf("unknown draft %q", draft)
+	}
+	schema, err := CompileJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("compile meta-schema %s: %w", draft, err)
+	}
+	metaSchemas.Store(draft, schema)
+	return schema, nil
+}
+
+// CheckSchema validates schema document against meta-schema of draft and reports keywords,
+// that the draft does not support. Returned error is ValidationErrors, if schema is invalid.
+// Instance locations of errors point into the schema document.
+func CheckSchema(data []byte, draft entity.DraftVersion) error {
+	metaSchema, err := MetaSchema(draft)
+	if err != nil {
+		return err
+	}
+	doc, err := decodeJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decode schema: %w", err)
+	}
+
+	var errs ValidationErrors
+	if result := metaSchema.evaluate(doc); !result.valid {
+		errs = result.validationErrors()
+	}
+	errs = append(errs, unsupportedKeywords(doc, "", draft)...)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// unsupportedKeywords finds keywords of schema and its subschemas, that draft does not support
+func unsupportedKeywords(raw any, pointer string, draft entity.DraftVersion) ValidationErrors {
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var errs ValidationErrors
+	for _, keyword := range sortedKeys(obj) {
+		if keywordRange, ok := draftKeywords[keyword]; ok && !keywordRange.contains(draft) {
+			errs = append(errs, &ValidationError{
+				Keyword:          keyword,
+				InstanceLocation: pointer + "/" + escapePointer(keyword),
+				Message:          fmt.Sprintf("keyword %s is not supported by %s", keyword, draft),
+			})
+		}
+	}
+
+	_ = entity.EachSubschema(obj, func(subschema any, subschemaPointer string) error {
+		errs = append(errs, unsupportedKeywords(subschema, pointer+subschemaPointer, draft)...)
+		return nil
+	})
+	return errs
+}
+
+func (r draftRange) contains(draft entity.DraftVersion) bool {
+	index := draftIndex(draft)
+	if r.since != "" && index < draftIndex(r.since) {
+		return false
+	}
+	return r.until == "" || index < draftIndex(r.until)
+}
+
+func draftIndex(draft entity.DraftVersion) int {
+	for i, known := range drafts {
+		if known == draft {
+			return i
+		}
+	}
+	return len(drafts)
+}
+
+// loadMetaSchema registers official meta-schema by its uri
+func (c *compiler) loadMetaSchema(uri string) (any, bool) {
+	data, ok := metaSchemaFile(uri)
+	if !ok {
+		return nil, false
+	}
+	doc, err := decodeJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, false
+	}
+	if err = c.addResource(uri, doc); err != nil {
+		return nil, false
+	}
+	return doc, true
+}
+
+// metaSchemaFile returns content of official meta-schema by its uri
+func metaSchemaFile(uri string) ([]byte, bool) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host != "json-schema.org" {
+		return nil, false
+	}
+	data, err := metaSchemaFS.ReadFile("metaschemas/" + strings.Trim(parsed.Path, "/") + ".json")
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,171 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OutputFormat is a standard output format of JSON Schema 2020-12
+type OutputFormat string
+
+const (
+	// OutputFlag contains only validation result
+	OutputFlag OutputFormat = "flag"
+	// OutputBasic contains flat list of errors
+	OutputBasic OutputFormat = "basic"
+	// OutputDetailed contains errors in hierarchy of schema, units with single error are condensed
+	OutputDetailed OutputFormat = "detailed"
+	// OutputVerbose contains results of all evaluated schemas and keywords
+	OutputVerbose OutputFormat = "verbose"
+)
+
+// OutputUnit is an output unit of JSON Schema 2020-12 output formats
+type OutputUnit struct {
+	Valid bool `json:"valid"`
+	// KeywordLocation is JSON Pointer to the keyword, following $ref through the path
+	KeywordLocation string `json:"keywordLocation"`
+	// AbsoluteKeywordLocation is absolute URI of the keyword after resolving references.
+	// It is empty, if schema has no absolute base URI.
+	AbsoluteKeywordLocation string `json:"absoluteKeywordLocation,omitempty"`
+	// InstanceLocation is JSON Pointer to the value of document
+	InstanceLocation string `json:"instanceLocation"`
+	Error            string `json:"error,omitempty"`
+	// Errors contains units of failed subschemas and keywords
+	Errors []*OutputUnit `json:"errors,omitempty"`
+	// Annotations contains units of passed subschemas and keywords in verbose format
+	Annotations []*OutputUnit `json:"annotations,omitempty"`
+
+	// flag is set for flag format, that contains only valid
+	flag bool
+}
+
+// MarshalJSON marshals only valid for flag format
+func (u *OutputUnit) MarshalJSON() ([]byte, error) {
+	if u.flag {
+		return json.Marshal(struct {
+			Valid bool `json:"valid"`
+		}{Valid: u.Valid})
+	}
+	type outputUnit OutputUnit
+	return json.Marshal((*outputUnit)(u))
+}
+
+// Evaluate validates any Go value and returns result in output format. The value is marshaled to JSON first.
+func (s *Schema) Evaluate(v any, format OutputFormat) (*OutputUnit, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal value: %w", err)
+	}
+	return s.EvaluateBytes(data, format)
+}
+
+// EvaluateBytes validates JSON document and returns result in output format
+func (s *Schema) EvaluateBytes(data []byte, format OutputFormat) (*OutputUnit, error) {
+	return s.EvaluateReader(bytes.NewReader(data), format)
+}
+
+// EvaluateReader validates JSON document read from reader and returns result in output format
+func (s *Schema) EvaluateReader(r io.Reader, format OutputFormat) (*OutputUnit, error) {
+	instance, err := decodeJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	result := s.evaluate(instance)
+
+	switch format {
+	case OutputFlag:
+		return &OutputUnit{Valid: result.valid, flag: true}, nil
+	case OutputBasic:
+		return result.basicOutput(), nil
+	case OutputDetailed:
+		return result.detailedOutput(), nil
+	case OutputVerbose:
+		return result.verboseOutput(), nil
+	default:
+		return nil, fmt.Errorf("unknown output format %s", format)
+	}
+}
+
+func (r *result) outputUnit() *OutputUnit {
+	return &OutputUnit{
+		Valid:                   r.valid,
+		KeywordLocation:         r.keywordLocation,
+		AbsoluteKeywordLocation: absoluteLocation(r.absoluteKeywordLocation),
+		InstanceLocation:        r.instanceLocation,
+		Error:                   r.message,
+	}
+}
+
+// basicOutput contains all failed units with error message in flat list
+func (r *result) basicOutput() *OutputUnit {
+	root := r.outputUnit()
+	root.Error = ""
+	var collect func(r *result)
+	collect = func(r *result) {
+		if r.valid {
+			return
+		}
+		if r.message != "" {
+			unit := r.outputUnit()
+			root.Errors = append(root.Errors, unit)
+		}
+		for _, child := range r.children {
+			collect(child)
+		}
+	}
+	collect(r)
+	return root
+}
+
+// detailedOutput contains failed units in hierarchy of schema. Units without own error and
+// with single failed child are replaced by the child.
+func (r *result) detailedOutput() *OutputUnit {
+	if r.valid {
+		return r.outputUnit()
+	}
+	var condense func(r *result) *OutputUnit
+	condense = func(r *result) *OutputUnit {
+		unit := r.outputUnit()
+		for _, child := range r.children {
+			if !child.valid {
+				unit.Errors = append(unit.Errors, condense(child))
+			}
+		}
+		if unit.Error == "" && len(unit.Errors) == 1 {
+			return unit.Errors[0]
+		}
+		return unit
+	}
+
+	root := condense(r)
+	if root.KeywordLocation != "" || root.InstanceLocation != "" {
+		// Root unit always describes the whole document
+		root = &OutputUnit{Errors: []*OutputUnit{root}}
+	}
+	return root
+}
+
+// verboseOutput contains units of all evaluated schemas and keywords
+func (r *result) verboseOutput() *OutputUnit {
+	unit := r.outputUnit()
+	for _, child := range r.children {
+		childUnit := child.verboseOutput()
+		if child.valid {
+			unit.Annotations = append(unit.Annotations, childUnit)
+		} else {
+			unit.Errors = append(unit.Errors, childUnit)
+		}
+	}
+	return unit
+}
+
+// absoluteLocation returns location, if it is absolute URI
+func absoluteLocation(location string) string {
+	if strings.HasPrefix(location, "#") {
+		return ""
+	}
+	return location
+}
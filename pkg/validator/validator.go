@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"io"
+	"strings"
+)
+
+var ErrReferenceCycle = errors.New("references form cycle without nested instance")
+
+// Schema is a compiled JSON Schema, that can validate JSON documents
+type Schema struct {
+	root           *node
+	dynamicAnchors map[string]*node
+	assertFormat   bool
+	formatCheckers map[string]FormatChecker
+}
+
+// Option configures compilation of schema
+type Option func(c *compiler)
+
+// WithFormatAssertion makes format keyword an assertion. By default format is only an annotation.
+func WithFormatAssertion() Option {
+	return func(c *compiler) {
+		c.assertFormat = true
+	}
+}
+
+// ValidationError describes single violation of schema keyword
+type ValidationError struct {
+	// Keyword is the failed keyword, e.g. minimum
+	Keyword string
+	// KeywordLocation is JSON Pointer to the keyword, following $ref through the path, e.g. /properties/a/minimum
+	KeywordLocation string
+	// AbsoluteKeywordLocation is absolute URI of the keyword after resolving references.
+	// It is empty, if schema has no absolute base URI.
+	AbsoluteKeywordLocation string
+	// InstanceLocation is JSON Pointer to the invalid value of document, e.g. /a
+	InstanceLocation string
+	// Message describes violation
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.KeywordLocation == "" {
+		return fmt.Sprintf("%s: %s", instanceLocationString(e.InstanceLocation), e.Message)
+	}
+	return fmt.Sprintf("%s: %s (%s)", instanceLocationString(e.InstanceLocation), e.Message, e.KeywordLocation)
+}
+
+// ValidationErrors contains all violations found in document
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "jsonschema validation failed:\n" + strings.Join(messages, "\n")
+}
+
+// Compile compiles schema of the entity model
+func Compile(schema *entity.JSONSchema, opts ...Option) (*Schema, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return CompileJSON(data, opts...)
+}
+
+// CompileJSON compiles schema from JSON document
+func CompileJSON(data []byte, opts ...Option) (*Schema, error) {
+	doc, err := decodeJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode schema: %w", err)
+	}
+
+	c := newCompiler()
+	for _, opt := range opts {
+		opt(c)
+	}
+	root, err := c.compileDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{
+		root:           root,
+		dynamicAnchors: c.dynamicAnchorNodes(),
+		assertFormat:   c.assertFormat,
+		formatCheckers: formatCheckers,
+	}, nil
+}
+
+// Validate validates any Go value. The value is marshaled to JSON first, so it is validated the same way
+// as its encoding/json representation. Use ValidateBytes for raw JSON documents.
+// Returned error is ValidationErrors, if document is invalid.
+func (s *Schema) Validate(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal value: %w", err)
+	}
+	return s.ValidateBytes(data)
+}
+
+// ValidateBytes validates JSON document
+func (s *Schema) ValidateBytes(data []byte) error {
+	return s.ValidateReader(bytes.NewReader(data))
+}
+
+// ValidateReader validates JSON document read from reader
+func (s *Schema) ValidateReader(r io.Reader) error {
+	instance, err := decodeJSON(r)
+	if err != nil {
+		return fmt.Errorf("decode document: %w", err)
+	}
+	result := s.evaluate(instance)
+	if result.valid {
+		return nil
+	}
+	return result.validationErrors()
+}
+
+func (s *Schema) evaluate(instance any) *result {
+	e := &evaluator{assertFormat: s.assertFormat, formatCheckers: s.formatCheckers, dynamicAnchors: s.dynamicAnchors}
+	return e.evaluate(s.root, instance, "", "")
+}
+
+// decodeJSON decodes single JSON value keeping numbers as json.Number
+func decodeJSON(r io.Reader) (any, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+func instanceLocationString(location string) string {
+	if location == "" {
+		return "(root)"
+	}
+	return location
+}
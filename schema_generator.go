@@ -6,10 +6,13 @@ import (
 	"github.com/paulrozhkin/jsonschema/pkg/converter"
 	"github.com/paulrozhkin/jsonschema/pkg/entity"
 	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
 )
 
 var ErrParserNotFound = errors.New("parser not found")
 var ErrConverterNotFound = errors.New("metadata to jsonschema converter not found")
+var ErrMultiConverterNotSupported = errors.New("converter does not support several root types")
+var ErrSchemaNotGenerated = errors.New("schema is not generated")
 
 type AfterParseFunc func(metadata *entity.JsonSchemaMetadata) error
 type AfterConvertFunc func(schema *entity.JSONSchema) error
@@ -40,25 +43,40 @@ func FromTypeToJsonSchema(obj any) (*SchemaGenerator, error) {
 	return generator, generator.Generate()
 }
 
+// FromPackageToJsonSchemas generates standalone schema for every type of package. Package is parsed once
+// for all types. If typeNames is empty, all exported structs of the package are used.
+// Schemas are keyed by type ID (<package>#<type name>).
+func FromPackageToJsonSchemas(packageName string, typeNames ...string) (map[string]*entity.JSONSchema, error) {
+	generator := DefaultGenerator()
+	generator.Parser = parser.NewAstParserForTypes(typeNames, packageName)
+	return generator.GenerateEach()
+}
+
+// FromPackageToJsonSchemaBundle generates single schema with every type of package under $defs.
+// If typeNames is empty, all exported structs of the package are used.
+func FromPackageToJsonSchemaBundle(packageName string, typeNames ...string) (*SchemaGenerator, error) {
+	generator := DefaultGenerator()
+	generator.Parser = parser.NewAstParserForTypes(typeNames, packageName)
+	return generator, generator.GenerateBundle()
+}
+
+// FromSourceToJsonSchema generates schema from Go sources in memory (filename to content) without module on disk.
+// Sources can import only standard library packages.
+func FromSourceToJsonSchema(typeName, packagePath string, sources map[string][]byte) (*SchemaGenerator, error) {
+	generator := DefaultGenerator()
+	generator.Parser = parser.NewAstSourceParser([]string{typeName}, packagePath, sources)
+	return generator, generator.Generate()
+}
+
 func FromFilesToJsonSchema() (*SchemaGenerator, error) {
 	panic("implement me")
 }
 
 func (g *SchemaGenerator) Generate() error {
-	// Parse go structs from any source to type metadata
-	if g.Parser == nil {
-		return ErrParserNotFound
-	}
-	metadata, err := g.Parser.Parse()
+	metadata, err := g.parse()
 	if err != nil {
 		return err
 	}
-	if g.AfterParse != nil {
-		err = g.AfterParse(metadata)
-		if err != nil {
-			return err
-		}
-	}
 
 	// Convert go type metadata to jsonschema struct
 	if g.Converter == nil {
@@ -68,17 +86,110 @@ func (g *SchemaGenerator) Generate() error {
 	if err != nil {
 		return err
 	}
-	if g.AfterConvert != nil {
-		err = g.AfterConvert(jsonSchema)
-		if err != nil {
-			return err
+	if err = g.afterConvert(jsonSchema); err != nil {
+		return err
+	}
+
+	g.jsonSchema = jsonSchema
+	return nil
+}
+
+// GenerateEach generates standalone schema for every root type parsed by Parser.
+// Schemas are keyed by type ID (<package>#<type name>).
+func (g *SchemaGenerator) GenerateEach() (map[string]*entity.JSONSchema, error) {
+	metadata, err := g.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	multiConverter, err := g.multiConverter()
+	if err != nil {
+		return nil, err
+	}
+	jsonSchemas, err := multiConverter.ConvertEach(g.Config, metadata)
+	if err != nil {
+		return nil, err
+	}
+	for _, jsonSchema := range jsonSchemas {
+		if err = g.afterConvert(jsonSchema); err != nil {
+			return nil, err
 		}
 	}
+	return jsonSchemas, nil
+}
+
+// GenerateBundle generates single schema with every type parsed by Parser under $defs
+func (g *SchemaGenerator) GenerateBundle() error {
+	metadata, err := g.parse()
+	if err != nil {
+		return err
+	}
+
+	multiConverter, err := g.multiConverter()
+	if err != nil {
+		return err
+	}
+	jsonSchema, err := multiConverter.ConvertBundle(g.Config, metadata)
+	if err != nil {
+		return err
+	}
+	if err = g.afterConvert(jsonSchema); err != nil {
+		return err
+	}
 
 	g.jsonSchema = jsonSchema
 	return nil
 }
 
+// parse go structs from any source to type metadata
+func (g *SchemaGenerator) parse() (*entity.JsonSchemaMetadata, error) {
+	if g.Parser == nil {
+		return nil, ErrParserNotFound
+	}
+	metadata, err := g.Parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+	if g.AfterParse != nil {
+		err = g.AfterParse(metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return metadata, nil
+}
+
+func (g *SchemaGenerator) multiConverter() (converter.MultiConverter, error) {
+	if g.Converter == nil {
+		return nil, ErrConverterNotFound
+	}
+	multiConverter, ok := g.Converter.(converter.MultiConverter)
+	if !ok {
+		return nil, ErrMultiConverterNotSupported
+	}
+	return multiConverter, nil
+}
+
+func (g *SchemaGenerator) afterConvert(jsonSchema *entity.JSONSchema) error {
+	if g.AfterConvert != nil {
+		return g.AfterConvert(jsonSchema)
+	}
+	return nil
+}
+
 func (g *SchemaGenerator) ToJson() ([]byte, error) {
 	return json.MarshalIndent(g.jsonSchema, "", "  ")
 }
+
+// Check validates generated schema against official meta-schema of Config.SchemaVersion and reports keywords,
+// that the draft does not support. Returned error is validator.ValidationErrors, if schema is invalid.
+func (g *SchemaGenerator) Check() error {
+	if g.jsonSchema == nil {
+		return ErrSchemaNotGenerated
+	}
+	data, err := json.Marshal(g.jsonSchema)
+	if err != nil {
+		return err
+	}
+	return validator.CheckSchema(data, g.Config.SchemaVersion)
+}
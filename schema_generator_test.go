@@ -1,24 +1,73 @@
-package jsonschema
+package jsonschema_test
 
 import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/jsonschematest"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
 	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/paulrozhkin/jsonschema/tests/generic"
 	"github.com/stretchr/testify/require"
-	"os"
 	"testing"
 )
 
 func TestGenerateSchemaFromType(t *testing.T) {
-	generator, err := FromTypeToJsonSchema(base.Settings{})
+	jsonschematest.AssertSchemaGolden(t, base.Settings{}, "./tests/output/settings.json")
+}
+
+func TestGenerateSchemaFromGenericType(t *testing.T) {
+	jsonschematest.AssertSchemaGolden(t, generic.Response{}, "./tests/output/response.json")
+}
+
+func TestGenerateSchemasForPackage(t *testing.T) {
+	schemas, err := jsonschema.FromPackageToJsonSchemas("github.com/paulrozhkin/jsonschema/tests/base")
+	require.NoError(t, err)
+	require.Len(t, schemas, 2)
+
+	actualJSON, err := json.MarshalIndent(schemas["github.com/paulrozhkin/jsonschema/tests/base#Settings"], "", "  ")
+	require.NoError(t, err)
+	jsonschematest.AssertGolden(t, actualJSON, "./tests/output/settings.json")
+}
+
+func TestGenerateSchemaBundleForPackage(t *testing.T) {
+	generator, err := jsonschema.FromPackageToJsonSchemaBundle("github.com/paulrozhkin/jsonschema/tests/base")
+	require.NoError(t, err)
+	actualJSON, err := generator.ToJson()
 	require.NoError(t, err)
-	compareSchemaOutput(t, generator, "./tests/output/settings.json")
+	jsonschematest.AssertGolden(t, actualJSON, "./tests/output/base_bundle.json")
 }
 
-func compareSchemaOutput(t *testing.T, generator *SchemaGenerator, filename string) {
-	t.Helper()
-	expectedJSON, err := os.ReadFile(filename)
+func TestGenerateSchemaFromSource(t *testing.T) {
+	sources := map[string][]byte{
+		"types.go": []byte("package snippet\n\ntype User struct {\n\tName string `json:\"name\"`\n}\n"),
+	}
+	generator, err := jsonschema.FromSourceToJsonSchema("User", "example.com/snippet", sources)
 	require.NoError(t, err)
 
 	actualJSON, err := generator.ToJson()
 	require.NoError(t, err)
-	require.JSONEq(t, string(expectedJSON), string(actualJSON))
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/snippet/User",
+		"type": "object",
+		"properties": {"name": {"type": "string"}},
+		"required": ["name"]
+	}`, string(actualJSON))
+}
+
+func TestCheckGeneratedSchema(t *testing.T) {
+	generator, err := jsonschema.FromTypeToJsonSchema(base.Settings{})
+	require.NoError(t, err)
+	require.NoError(t, generator.Check())
+
+	generator.Config.SchemaVersion = entity.Draft07
+	require.NoError(t, generator.Generate())
+	var validationErrors validator.ValidationErrors
+	require.ErrorAs(t, generator.Check(), &validationErrors)
+	require.Len(t, validationErrors, 1)
+	require.Equal(t, "$defs", validationErrors[0].Keyword)
+	require.Equal(t, "/$defs", validationErrors[0].InstanceLocation)
+
+	require.ErrorIs(t, jsonschema.DefaultGenerator().Check(), jsonschema.ErrSchemaNotGenerated)
 }
@@ -13,6 +13,11 @@ type Settings struct {
 	FloatValue       float32                   `json:"floatValue"`
 }
 
+type Account struct {
+	Name     string                   `json:"name"`
+	Settings additional.InnerSettings `json:"settings"`
+}
+
 func ExpectedSettingsMetadata() *entity.JsonSchemaMetadata {
 	jsonSchemaMetadata := entity.NewJsonSchemaMetadata()
 	packageAdditional := reflect.TypeOf(additional.InnerSettings{}).PkgPath()
@@ -0,0 +1,20 @@
+package api
+
+import "fmt"
+
+//jsonschema:generate
+type User struct {
+	Name string `json:"name"`
+}
+
+type UserRequest struct {
+	ID int `json:"id"`
+}
+
+func (r UserRequest) String() string {
+	return fmt.Sprintf("user request %d", r.ID)
+}
+
+type UserResponse struct {
+	User User `json:"user"`
+}
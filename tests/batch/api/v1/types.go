@@ -0,0 +1,12 @@
+package v1
+
+// Order is a purchase order
+//
+//jsonschema:generate
+type Order struct {
+	Amount float64 `json:"amount"`
+}
+
+type OrderRequest struct {
+	ID int `json:"id"`
+}
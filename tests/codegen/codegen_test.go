@@ -0,0 +1,118 @@
+package codegen
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/codegen"
+	"github.com/paulrozhkin/jsonschema/tests/codegen/catalog"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestGenerateFromJSON(t *testing.T) {
+	schemaJSON, err := os.ReadFile("catalog.json")
+	require.NoError(t, err)
+	expected, err := os.ReadFile("catalog/types.go")
+	require.NoError(t, err)
+
+	source, err := codegen.GenerateFromJSON(schemaJSON, codegen.Config{PackageName: "catalog"})
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(source))
+}
+
+func TestRoundTrip(t *testing.T) {
+	schemaJSON, err := os.ReadFile("catalog.json")
+	require.NoError(t, err)
+
+	generator, err := jsonschema.FromTypeToJsonSchema(catalog.Product{})
+	require.NoError(t, err)
+	actualJSON, err := generator.ToJson()
+	require.NoError(t, err)
+
+	// Only $id depends on package of generated types
+	var actual map[string]any
+	require.NoError(t, json.Unmarshal(actualJSON, &actual))
+	actual["$id"] = "https://example.com/catalog/Product"
+	actualJSON, err = json.Marshal(actual)
+	require.NoError(t, err)
+	require.JSONEq(t, string(schemaJSON), string(actualJSON))
+}
+
+func TestGenerateTypes(t *testing.T) {
+	schemaJSON := `{
+		"title": "order event",
+		"type": "object",
+		"properties": {
+			"order_id": {"type": "integer", "description": "Identifier of order\nin the shop"},
+			"items": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {"sku": {"type": "string", "pattern": "^[a-z]{2,4}$"}},
+					"required": ["sku"]
+				}
+			},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"comment": {"type": ["string", "null"]},
+			"priority": {"type": "integer", "enum": [-1, 0, 1]},
+			"payload": {"anyOf": [{"type": "string"}, {"type": "integer"}]}
+		},
+		"required": ["order_id", "items"]
+	}`
+
+	source, err := codegen.GenerateFromJSON([]byte(schemaJSON), codegen.Config{PackageName: "events"})
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by jsonschema codegen. DO NOT EDIT.
+
+package events
+
+type OrderEvent struct {
+	// Identifier of order
+	// in the shop
+	OrderID  int                   `+"`"+`json:"order_id"`+"`"+`
+	Items    []OrderEventItemsItem `+"`"+`json:"items"`+"`"+`
+	Labels   map[string]string     `+"`"+`json:"labels,omitempty"`+"`"+`
+	Comment  *string               `+"`"+`json:"comment,omitempty"`+"`"+`
+	Priority *OrderEventPriority   `+"`"+`json:"priority,omitempty" jsonschema:"enum=-1,enum=0,enum=1"`+"`"+`
+	Payload  any                   `+"`"+`json:"payload,omitempty"`+"`"+`
+}
+
+type OrderEventItemsItem struct {
+	// jsonschema: pattern=^[a-z]{2,4}$ can not be expressed in struct tag
+	Sku string `+"`"+`json:"sku"`+"`"+`
+}
+
+type OrderEventPriority int
+
+const (
+	OrderEventPriorityMinus1 OrderEventPriority = -1
+	OrderEventPriority0      OrderEventPriority = 0
+	OrderEventPriority1      OrderEventPriority = 1
+)
+`, string(source))
+}
+
+func TestGenerateFieldNames(t *testing.T) {
+	schemaJSON := `{"type": "object", "properties": {"user_id": {"type": "string"}, "userId": {"type": "string"},
+		"_": {"type": "string"}, "2fa": {"type": "boolean"}}, "required": ["user_id", "userId", "_", "2fa"]}`
+
+	source, err := codegen.GenerateFromJSON([]byte(schemaJSON), codegen.Config{PackageName: "users"})
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by jsonschema codegen. DO NOT EDIT.
+
+package users
+
+type Root struct {
+	UserID   string `+"`"+`json:"user_id"`+"`"+`
+	UserID2  string `+"`"+`json:"userId"`+"`"+`
+	Field    string `+"`"+`json:"_"`+"`"+`
+	Field2fa bool   `+"`"+`json:"2fa"`+"`"+`
+}
+`, string(source))
+}
+
+func TestGeneratePackageNameRequired(t *testing.T) {
+	_, err := codegen.GenerateFromJSON([]byte(`{"type": "object"}`), codegen.Config{})
+	require.ErrorIs(t, err, codegen.ErrPackageNameNotFound)
+}
@@ -0,0 +1,113 @@
+package compatibility
+
+import (
+	"errors"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/compatibility"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	paymentV1 = `{"type": "object", "properties": {"id": {"type": "string"},
+		"amount": {"type": "integer", "maximum": 100}}, "required": ["id"]}`
+	paymentV2 = `{"type": "object", "properties": {"id": {"type": "string"},
+		"amount": {"type": "integer", "maximum": 200}}, "required": ["id"]}`
+	paymentV3 = `{"type": "object", "properties": {"id": {"type": "string"},
+		"amount": {"type": "integer", "maximum": 150}}, "required": ["id"]}`
+)
+
+func TestCheck(t *testing.T) {
+	history := [][]byte{[]byte(paymentV1), []byte(paymentV2)}
+	tests := []struct {
+		level    compatibility.Level
+		versions []int
+	}{
+		{level: compatibility.None},
+		{level: compatibility.Backward, versions: []int{2}},
+		{level: compatibility.BackwardTransitive, versions: []int{2}},
+		{level: compatibility.Forward},
+		{level: compatibility.ForwardTransitive, versions: []int{1}},
+		{level: compatibility.Full, versions: []int{2}},
+		{level: compatibility.FullTransitive, versions: []int{2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			err := compatibility.Check(tt.level, history, []byte(paymentV3))
+			require.Equal(t, tt.versions, incompatibleVersions(t, err))
+		})
+	}
+}
+
+func TestCheckIncompatibleError(t *testing.T) {
+	err := compatibility.Check(compatibility.Backward, [][]byte{[]byte(paymentV2)}, []byte(paymentV3))
+	require.EqualError(t, err,
+		"schema is not BACKWARD compatible with version 1: #/properties/amount: maximum tightened from 200 to 150")
+}
+
+func TestCheckHistory(t *testing.T) {
+	history := [][]byte{[]byte(paymentV1), []byte(paymentV2), []byte(paymentV3)}
+	require.NoError(t, compatibility.CheckHistory(compatibility.Backward, history[:2]))
+
+	err := compatibility.CheckHistory(compatibility.Backward, history)
+	require.ErrorContains(t, err, "version 3: schema is not BACKWARD compatible with version 2")
+	err = compatibility.CheckHistory(compatibility.Forward, history)
+	require.ErrorContains(t, err, "version 2: schema is not FORWARD compatible with version 1")
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := compatibility.ParseLevel("full_transitive")
+	require.NoError(t, err)
+	require.Equal(t, compatibility.FullTransitive, level)
+	require.True(t, level.Transitive())
+
+	_, err = compatibility.ParseLevel("strict")
+	require.ErrorIs(t, err, compatibility.ErrUnknownLevel)
+	err = compatibility.Check("strict", nil, []byte(paymentV1))
+	require.ErrorIs(t, err, compatibility.ErrUnknownLevel)
+}
+
+type PaymentV1 struct {
+	ID     string `json:"id"`
+	Amount *int   `json:"amount,omitempty"`
+}
+
+type PaymentV2 struct {
+	ID       string `json:"id"`
+	Amount   *int   `json:"amount,omitempty"`
+	Currency string `json:"currency"`
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	generator, err := jsonschema.FromTypeToJsonSchema(PaymentV1{})
+	require.NoError(t, err)
+	data, err := generator.ToJson()
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "payment-v1.json")
+	require.NoError(t, os.WriteFile(filename, data, 0o644))
+
+	require.NoError(t, jsonschema.CheckCompatibility(PaymentV1{}, compatibility.Full, filename))
+	// New required property rejects data of previous version
+	err = jsonschema.CheckCompatibility(PaymentV2{}, compatibility.Backward, filename)
+	var incompatibleErr *compatibility.IncompatibleError
+	require.ErrorAs(t, err, &incompatibleErr)
+	require.Equal(t, 1, incompatibleErr.Version)
+}
+
+// incompatibleVersions returns versions of incompatible errors
+func incompatibleVersions(t *testing.T, err error) []int {
+	if err == nil {
+		return nil
+	}
+	joinErr, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok, err)
+	var versions []int
+	for _, versionErr := range joinErr.Unwrap() {
+		var incompatibleErr *compatibility.IncompatibleError
+		require.True(t, errors.As(versionErr, &incompatibleErr), versionErr)
+		versions = append(versions, incompatibleErr.Version)
+	}
+	return versions
+}
@@ -1,8 +1,11 @@
 package converter
 
 import (
+	"encoding/json"
 	"github.com/paulrozhkin/jsonschema/pkg/converter"
 	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
 	"github.com/paulrozhkin/jsonschema/tests/base"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -18,3 +21,263 @@ func TestConvertMetadataToJSONSchema(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, expectedJsonSchema, result)
 }
+
+func TestConvertEachRootType(t *testing.T) {
+	expectedMetadata := base.ExpectedSettingsMetadata()
+	innerSettings := expectedMetadata.Root.Nodes[0].Ref
+	expectedMetadata.Roots = []*entity.DataTypeMetadata{expectedMetadata.Root, innerSettings}
+	expectedMetadata.Root = nil
+	cfg := entity.Config{SchemaVersion: entity.Draft202012}
+
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	result, err := schemaConverter.ConvertEach(cfg, expectedMetadata)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, base.ExpectedSettingsJsonSchema(), result[expectedMetadata.Roots[0].ID()])
+
+	innerSettingsSchema := result[innerSettings.ID()]
+	require.Empty(t, innerSettingsSchema.Defs)
+	require.Equal(t, []string{"stringValue", "intValue", "boolValue"}, innerSettingsSchema.Properties.Keys())
+}
+
+func TestConvertBundle(t *testing.T) {
+	expectedMetadata := base.ExpectedSettingsMetadata()
+	cfg := entity.Config{SchemaVersion: entity.Draft202012}
+
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	result, err := schemaConverter.ConvertBundle(cfg, expectedMetadata)
+	require.NoError(t, err)
+	require.Equal(t, []string{"InnerSettings", "Settings"}, result.Defs.Keys())
+	require.Empty(t, result.Properties)
+}
+
+func TestConvertSortProperties(t *testing.T) {
+	expectedMetadata := base.ExpectedSettingsMetadata()
+	cfg := entity.Config{SchemaVersion: entity.Draft202012, SortProperties: true}
+
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	result, err := schemaConverter.Convert(cfg, expectedMetadata)
+	require.NoError(t, err)
+	require.Equal(t, []string{"floatValue", "refInnerSettings", "valInnerSettings"}, result.Properties.Keys())
+	innerSettings, ok := result.Defs.Get("InnerSettings")
+	require.True(t, ok)
+	require.Equal(t, []string{"boolValue", "intValue", "stringValue"},
+		innerSettings.(*entity.ObjectSchema).Properties.Keys())
+	// Required keyword keeps order of fields
+	require.Equal(t, []string{"valInnerSettings", "floatValue"}, result.Required)
+}
+
+func TestConvertExternalRefs(t *testing.T) {
+	expectedMetadata := base.ExpectedSettingsMetadata()
+	innerSettings := expectedMetadata.Root.Nodes[0].Ref
+	expectedMetadata.Roots = []*entity.DataTypeMetadata{expectedMetadata.Root, innerSettings}
+	expectedMetadata.Root = nil
+	cfg := entity.Config{SchemaVersion: entity.Draft202012, ExternalRefs: true}
+
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	result, err := schemaConverter.ConvertEach(cfg, expectedMetadata)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	settingsSchema := result[expectedMetadata.Roots[0].ID()]
+	require.Nil(t, settingsSchema.Defs)
+	innerSettingsSchema := result[innerSettings.ID()]
+	for _, name := range []string{"valInnerSettings", "refInnerSettings"} {
+		property, ok := settingsSchema.Properties.Get(name)
+		require.True(t, ok)
+		require.Equal(t, *innerSettingsSchema.ID, *property.(*entity.JSONSchema).Ref)
+	}
+	require.Equal(t, "https://github.com/paulrozhkin/jsonschema/tests/additional/InnerSettings", *innerSettingsSchema.ID)
+}
+
+func TestConvertSchemaIDTemplate(t *testing.T) {
+	cfg := entity.Config{
+		SchemaVersion:    entity.Draft202012,
+		SchemaIDBase:     "https://schemas.ourco.io/",
+		SchemaIDTemplate: "{base}/{version}/{package_name}/{type}.json",
+		SchemaIDVersion:  "v2",
+		ExternalRefs:     true,
+	}
+
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	result, err := schemaConverter.Convert(cfg, base.ExpectedSettingsMetadata())
+	require.NoError(t, err)
+	require.Equal(t, "https://schemas.ourco.io/v2/base/Settings.json", *result.ID)
+	property, _ := result.Properties.Get("valInnerSettings")
+	require.Equal(t, "https://schemas.ourco.io/v2/additional/InnerSettings.json", *property.(*entity.JSONSchema).Ref)
+
+	bundle, err := schemaConverter.ConvertBundle(cfg, base.ExpectedSettingsMetadata())
+	require.NoError(t, err)
+	require.Equal(t, "https://schemas.ourco.io", *bundle.ID)
+
+	cfg.SchemaIDTemplate = "{type}"
+	_, err = schemaConverter.Convert(cfg, base.ExpectedSettingsMetadata())
+	require.ErrorIs(t, err, converter.ErrInvalidSchemaID)
+}
+
+func TestConvertOmitSchemaID(t *testing.T) {
+	cfg := entity.Config{SchemaVersion: entity.Draft202012, OmitSchemaID: true}
+
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	result, err := schemaConverter.Convert(cfg, base.ExpectedSettingsMetadata())
+	require.NoError(t, err)
+	require.Nil(t, result.ID)
+
+	bundle, err := schemaConverter.ConvertBundle(cfg, base.ExpectedSettingsMetadata())
+	require.NoError(t, err)
+	require.Nil(t, bundle.ID)
+}
+
+func TestConvertDefinitionIDs(t *testing.T) {
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	cfg := entity.Config{SchemaVersion: entity.Draft202012, DefinitionIDs: entity.DefinitionSchemaID}
+	result, err := schemaConverter.Convert(cfg, base.ExpectedSettingsMetadata())
+	require.NoError(t, err)
+	innerSettings, ok := result.Defs.Get("InnerSettings")
+	require.True(t, ok)
+	require.Equal(t, "https://github.com/paulrozhkin/jsonschema/tests/additional/InnerSettings",
+		*innerSettings.(*entity.JSONSchema).ID)
+	require.Equal(t, []string{"stringValue", "intValue", "boolValue"},
+		innerSettings.(*entity.JSONSchema).Properties.Keys())
+
+	cfg.DefinitionIDs = entity.DefinitionAnchor
+	result, err = schemaConverter.Convert(cfg, base.ExpectedSettingsMetadata())
+	require.NoError(t, err)
+	innerSettings, _ = result.Defs.Get("InnerSettings")
+	require.Nil(t, innerSettings.(*entity.JSONSchema).ID)
+	require.Equal(t, "InnerSettings", *innerSettings.(*entity.JSONSchema).Anchor)
+}
+
+type DefinitionB struct {
+	X string `json:"x"`
+}
+
+type DefinitionA struct {
+	B DefinitionB `json:"b"`
+}
+
+type DefinitionRoot struct {
+	A DefinitionA `json:"a"`
+}
+
+func TestConvertDefinitionIDsResolve(t *testing.T) {
+	metadata, err := parser.NewReflectParser(DefinitionRoot{}).Parse()
+	require.NoError(t, err)
+	schemaConverter := converter.NewMetaToSchemaConverter()
+	for _, mode := range []entity.DefinitionIDMode{entity.DefinitionSchemaID, entity.DefinitionAnchor} {
+		t.Run(string(mode), func(t *testing.T) {
+			cfg := entity.Config{SchemaVersion: entity.Draft202012, SchemaIDBase: "https://main", DefinitionIDs: mode}
+			schema, err := schemaConverter.Convert(cfg, metadata)
+			require.NoError(t, err)
+			compiled, err := validator.Compile(schema)
+			require.NoError(t, err)
+			require.NoError(t, compiled.ValidateBytes([]byte(`{"a": {"b": {"x": "value"}}}`)))
+			require.Error(t, compiled.ValidateBytes([]byte(`{"a": {"b": {"x": 1}}}`)))
+		})
+	}
+
+	cfg := entity.Config{SchemaVersion: entity.Draft202012, SchemaIDBase: "https://main",
+		DefinitionIDs: entity.DefinitionSchemaID}
+	schema, err := schemaConverter.Convert(cfg, metadata)
+	require.NoError(t, err)
+	definitionA, _ := schema.Defs.Get("DefinitionA")
+	propertyB, _ := definitionA.(*entity.JSONSchema).Properties.Get("b")
+	require.Equal(t, "https://main/DefinitionB", *propertyB.(*entity.JSONSchema).Ref)
+}
+
+func TestConvertCollections(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type Collections struct {
+		Items  []Item           `json:"items"`
+		Tags   *[]string        `json:"tags,omitempty"`
+		Counts map[string]int   `json:"counts"`
+		Data   []byte           `json:"data"`
+		Raw    json.RawMessage  `json:"raw"`
+		Any    any              `json:"any"`
+		Values map[string]*Item `json:"values"`
+	}
+	metadata, err := parser.NewReflectParser(Collections{}).Parse()
+	require.NoError(t, err)
+
+	schema, err := converter.NewMetaToSchemaConverter().Convert(entity.Config{}, metadata)
+	require.NoError(t, err)
+	actual, err := json.Marshal(schema.Properties)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"items": {"type": "array", "items": {"$ref": "#/$defs/Item"}},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"counts": {"type": "object", "additionalProperties": {"type": "integer"}},
+		"data": {"type": "string", "contentEncoding": "base64"},
+		"raw": {},
+		"any": {},
+		"values": {"type": "object", "additionalProperties": {"$ref": "#/$defs/Item"}}
+	}`, string(actual))
+	require.Equal(t, []string{"Item"}, schema.Defs.Keys())
+}
+
+func TestConvertNestedCollections(t *testing.T) {
+	type Matrix struct {
+		Rows [][]int `json:"rows"`
+	}
+	metadata, err := parser.NewReflectParser(Matrix{}).Parse()
+	require.NoError(t, err)
+
+	_, err = converter.NewMetaToSchemaConverter().Convert(entity.Config{}, metadata)
+	require.ErrorContains(t, err, "not supported element type")
+}
+
+func TestConvertOmitemptyRequired(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type Optional struct {
+		ID      string            `json:"id"`
+		Note    string            `json:"note,omitempty"`
+		Tags    []string          `json:"tags,omitempty"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Pointer *Item             `json:"pointer"`
+		Item    Item              `json:"item,omitempty"`
+		Untyped string
+	}
+	metadata, err := parser.NewReflectParser(Optional{}).Parse()
+	require.NoError(t, err)
+
+	schema, err := converter.NewMetaToSchemaConverter().Convert(entity.Config{}, metadata)
+	require.NoError(t, err)
+	// encoding/json never omits struct values, so struct field with omitempty is required
+	require.Equal(t, []string{"id", "item", "Untyped"}, schema.Required)
+}
+
+func TestConvertDefinitionConflict(t *testing.T) {
+	orderItem := entity.NewDataTypeMetadata("example.com/order", "Item", "struct", false)
+	stockItem := entity.NewDataTypeMetadata("example.com/stock", "Item", "struct", false)
+	root := entity.NewDataTypeMetadata("example.com/order", "Order", "struct", false)
+	root.Nodes = []*entity.DataTypeMetadata{entity.NewDataTypeRefMetadata(orderItem),
+		entity.NewDataTypeRefMetadata(stockItem)}
+	root.Nodes[0].FieldName = "Item"
+	root.Nodes[1].FieldName = "Stock"
+	metadata := &entity.JsonSchemaMetadata{
+		Types: map[string]*entity.DataTypeMetadata{root.ID(): root, orderItem.ID(): orderItem,
+			stockItem.ID(): stockItem},
+		Roots: []*entity.DataTypeMetadata{root, stockItem},
+	}
+	schemaConverter := converter.NewMetaToSchemaConverter()
+
+	_, err := schemaConverter.ConvertBundle(entity.Config{OmitSchemaID: true}, metadata)
+	require.ErrorIs(t, err, converter.ErrDefinitionConflict)
+	require.EqualError(t, err, "types of different packages have the same definition name: "+
+		"example.com/order#Item and example.com/stock#Item")
+
+	_, err = schemaConverter.Convert(entity.Config{OmitSchemaID: true}, &entity.JsonSchemaMetadata{
+		Types: metadata.Types, Root: root})
+	require.ErrorIs(t, err, converter.ErrDefinitionConflict)
+
+	// Only types of root package are definitions with external references
+	schema, err := schemaConverter.Convert(entity.Config{OmitSchemaID: true, ExternalRefs: true,
+		SchemaIDTemplate: "https://example.com/{package}/{type}"}, &entity.JsonSchemaMetadata{
+		Types: metadata.Types, Root: root})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Item"}, schema.Defs.Keys())
+}
@@ -0,0 +1,170 @@
+package diff
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/diff"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type UserV1 struct {
+	Name     string  `json:"name"`
+	Age      int     `json:"age" jsonschema:"minimum=0,maximum=150"`
+	Nickname *string `json:"nickname,omitempty"`
+}
+
+type UserV2 struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age" jsonschema:"minimum=0,maximum=120"`
+	Email string `json:"email" jsonschema:"format=email"`
+}
+
+func TestCompareGeneratedSchemas(t *testing.T) {
+	oldGenerator, err := jsonschema.FromTypeToJsonSchema(UserV1{})
+	require.NoError(t, err)
+	oldSchema, err := oldGenerator.ToJson()
+	require.NoError(t, err)
+	newGenerator, err := jsonschema.FromTypeToJsonSchema(UserV2{})
+	require.NoError(t, err)
+	newSchema, err := newGenerator.ToJson()
+	require.NoError(t, err)
+
+	report, err := diff.CompareJSON(oldSchema, newSchema)
+	require.NoError(t, err)
+	require.Equal(t, []diff.Change{
+		{Kind: diff.KindRequiredAdded, Location: "/required", Message: `property "email" is required`,
+			BreaksProducers: true},
+		{Kind: diff.KindConstraintChanged, Location: "/properties/age", Message: "maximum tightened from 150 to 120",
+			BreaksProducers: true},
+		{Kind: diff.KindPropertyAdded, Location: "/properties/email",
+			Message: `property "email" added, old schema accepts any value of it`, BreaksProducers: true},
+		{Kind: diff.KindPropertyRemoved, Location: "/properties/nickname",
+			Message: `property "nickname" removed, schema accepts any value of it`, BreaksConsumers: true},
+	}, report.Changes)
+	require.True(t, report.BreaksProducers())
+	require.True(t, report.BreaksConsumers())
+}
+
+func TestCompareJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldSchema string
+		newSchema string
+		expected  []diff.Change
+	}{
+		{
+			name:      "narrowed enum",
+			oldSchema: `{"enum": ["a", "b", "c"]}`,
+			newSchema: `{"enum": ["a", "b"]}`,
+			expected: []diff.Change{{Kind: diff.KindEnumChanged, Message: `values ["c"] removed`,
+				BreaksProducers: true}},
+		},
+		{
+			name:      "integer widened to number",
+			oldSchema: `{"type": "integer"}`,
+			newSchema: `{"type": ["number", "null"]}`,
+			expected: []diff.Change{{Kind: diff.KindTypeChanged, Message: "type changed from integer to number, null",
+				BreaksConsumers: true}},
+		},
+		{
+			name:      "closed object",
+			oldSchema: `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			newSchema: `{"type": "object", "properties": {"a": {"type": "string"}}, "additionalProperties": false}`,
+			expected: []diff.Change{{Kind: diff.KindAdditionalPropertiesChanged, Location: "/additionalProperties",
+				Message: "additional properties are not allowed anymore", BreaksProducers: true}},
+		},
+		{
+			name: "property added to closed object",
+			oldSchema: `{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"],
+				"additionalProperties": false}`,
+			newSchema: `{"type": "object", "properties": {"a": {"type": "string"}, "b": {"type": "string"}},
+				"required": ["a"], "additionalProperties": false}`,
+			expected: []diff.Change{{Kind: diff.KindPropertyAdded, Location: "/properties/b",
+				Message: `property "b" added, old schema does not allow additional properties`, BreaksConsumers: true}},
+		},
+		{
+			name:      "changed definition of recursive type",
+			oldSchema: `{"$ref": "#/$defs/Node", "$defs": {"Node": {"properties": {"next": {"$ref": "#/$defs/Node"}, "value": {"type": "string", "maxLength": 10}}}}}`,
+			newSchema: `{"$ref": "#/$defs/Node", "$defs": {"Node": {"properties": {"next": {"$ref": "#/$defs/Node"}, "value": {"type": "string", "maxLength": 20}}}}}`,
+			expected: []diff.Change{{Kind: diff.KindConstraintChanged, Location: "/properties/value",
+				Message: "maxLength relaxed from 10 to 20", BreaksConsumers: true}},
+		},
+		{
+			name:      "tightened not",
+			oldSchema: `{"not": {"type": "string", "minLength": 5}}`,
+			newSchema: `{"not": {"type": "string", "minLength": 10}}`,
+			expected: []diff.Change{{Kind: diff.KindConstraintChanged, Location: "/not",
+				Message: "minLength tightened from 5 to 10", BreaksConsumers: true}},
+		},
+		{
+			name:      "changed description",
+			oldSchema: `{"type": "string", "description": "name"}`,
+			newSchema: `{"type": "string", "description": "full name"}`,
+			expected: []diff.Change{{Kind: diff.KindAnnotationChanged,
+				Message: `description changed from "name" to "full name"`}},
+		},
+		{
+			name:      "same schemas",
+			oldSchema: `{"$id": "https://example.com/v1", "type": "string"}`,
+			newSchema: `{"$id": "https://example.com/v2", "type": "string"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := diff.CompareJSON([]byte(tt.oldSchema), []byte(tt.newSchema))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, report.Changes)
+		})
+	}
+}
+
+func TestCompareReferenceNotFound(t *testing.T) {
+	_, err := diff.CompareJSON([]byte(`{"$ref": "#/$defs/A"}`), []byte(`{}`))
+	require.ErrorIs(t, err, diff.ErrReferenceNotFound)
+}
+
+func TestCompareReferenceCycle(t *testing.T) {
+	for _, schema := range []string{
+		`{"$ref": "#"}`,
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`,
+	} {
+		_, err := diff.CompareJSON([]byte(schema), []byte(`{}`))
+		require.ErrorIs(t, err, diff.ErrReferenceCycle)
+		_, err = diff.CompareJSON([]byte(`{}`), []byte(schema))
+		require.ErrorIs(t, err, diff.ErrReferenceCycle)
+	}
+
+	// Recursion through properties is not a cycle of references
+	recursive := `{"type": "object", "properties": {"child": {"$ref": "#"}}}`
+	report, err := diff.CompareJSON([]byte(recursive), []byte(recursive))
+	require.NoError(t, err)
+	require.Empty(t, report.Changes)
+}
+
+func TestReport(t *testing.T) {
+	report, err := diff.CompareJSON([]byte(`{"type": "object", "required": ["a"], "description": "user"}`),
+		[]byte(`{"type": "object", "required": ["b"], "description": "account"}`))
+	require.NoError(t, err)
+	require.True(t, report.Breaking())
+	require.Equal(t, `[breaks producers] #/required: property "b" is required
+[breaks consumers] #/required: property "a" is not required anymore
+[compatible] #: description changed from "user" to "account"
+`, report.String())
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"changes": [
+		{"kind": "required-added", "location": "/required", "message": "property \"b\" is required",
+			"breaksProducers": true, "breaksConsumers": false},
+		{"kind": "required-removed", "location": "/required", "message": "property \"a\" is not required anymore",
+			"breaksProducers": false, "breaksConsumers": true},
+		{"kind": "annotation-changed", "location": "", "message": "description changed from \"user\" to \"account\"",
+			"breaksProducers": false, "breaksConsumers": false}
+	]}`, string(data))
+
+	report, err = diff.CompareJSON([]byte(`true`), []byte(`{}`))
+	require.NoError(t, err)
+	require.False(t, report.Breaking())
+	require.Equal(t, "no changes\n", report.String())
+}
@@ -0,0 +1,161 @@
+package fake
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema/pkg/fake"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+const userSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"login": {"type": "string", "pattern": "^[a-z][a-z0-9_]{2,15}$"},
+		"email": {"type": "string", "format": "email"},
+		"created": {"type": "string", "format": "date-time"},
+		"age": {"type": "integer", "minimum": 18, "exclusiveMaximum": 120},
+		"score": {"type": "number", "minimum": 0, "maximum": 1, "multipleOf": 0.25},
+		"role": {"enum": ["admin", "editor", "viewer"]},
+		"tags": {"type": "array", "items": {"type": "string", "minLength": 2}, "minItems": 1, "uniqueItems": true},
+		"address": {"$ref": "#/$defs/Address"}
+	},
+	"required": ["id", "login", "email", "age", "role", "tags", "address"],
+	"additionalProperties": false,
+	"$defs": {
+		"Address": {
+			"type": "object",
+			"properties": {"zip": {"type": "string", "pattern": "^\\d{5}(-\\d{4})?$"}, "next": {"$ref": "#/$defs/Address"}},
+			"required": ["zip"]
+		}
+	}
+}`
+
+func TestGenerateJSON(t *testing.T) {
+	compiled, err := validator.CompileJSON([]byte(userSchema), validator.WithFormatAssertion())
+	require.NoError(t, err)
+	generator := fake.New(42)
+	for i := 0; i < 50; i++ {
+		instance, err := generator.GenerateJSON([]byte(userSchema))
+		require.NoError(t, err)
+		require.NoError(t, compiled.ValidateBytes(instance), string(instance))
+	}
+}
+
+func TestGenerateJSONDeterministic(t *testing.T) {
+	generate := func(seed int64) []string {
+		generator := fake.New(seed)
+		var instances []string
+		for i := 0; i < 5; i++ {
+			instance, err := generator.GenerateJSON([]byte(userSchema))
+			require.NoError(t, err)
+			instances = append(instances, string(instance))
+		}
+		return instances
+	}
+	require.Equal(t, generate(7), generate(7))
+	require.NotEqual(t, generate(7), generate(8))
+}
+
+func TestGenerateJSONOptions(t *testing.T) {
+	schema := []byte(`{"type": "object", "properties": {"name": {"type": "string"},
+		"items": {"type": "array", "items": {"type": "integer"}}}}`)
+	instance, err := fake.New(1, fake.WithOptionalProbability(0)).GenerateJSON(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{}`, string(instance))
+
+	instance, err = fake.New(1, fake.WithOptionalProbability(1), fake.WithMaxItems(0)).GenerateJSON(schema)
+	require.NoError(t, err)
+	var value map[string]any
+	require.NoError(t, json.Unmarshal(instance, &value))
+	require.Contains(t, value, "name")
+	require.Equal(t, []any{}, value["items"])
+}
+
+func TestGenerateJSONUnsatisfiable(t *testing.T) {
+	tests := []string{
+		`false`,
+		`{"enum": []}`,
+		`{"type": "string", "pattern": "^a+$", "maxLength": 0, "minLength": 1}`,
+		`{"type": "integer", "minimum": 5, "maximum": 1}`,
+		`{"type": "string", "not": {"type": "string"}}`,
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			_, err := fake.New(1).GenerateJSON([]byte(tt))
+			require.ErrorIs(t, err, fake.ErrUnsatisfiable)
+		})
+	}
+
+	_, err := fake.New(1).GenerateJSON([]byte(`{"$ref": "#/$defs/Missing"}`))
+	require.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	schema := base.ExpectedSettingsJsonSchema()
+	instance, err := fake.New(3).Generate(schema)
+	require.NoError(t, err)
+
+	var settings base.Settings
+	data, err := json.Marshal(instance)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &settings))
+	require.GreaterOrEqual(t, settings.ValInnerSettings.IntValue, 0)
+	require.LessOrEqual(t, settings.ValInnerSettings.IntValue, 10)
+}
+
+func TestPopulateExamples(t *testing.T) {
+	schema := base.ExpectedSettingsJsonSchema()
+	require.NoError(t, fake.PopulateExamples(schema, 5, 2))
+	require.Len(t, schema.Examples, 2)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	compiled, err := validator.CompileJSON(data)
+	require.NoError(t, err)
+	for _, example := range schema.Examples {
+		require.NoError(t, compiled.Validate(*example))
+	}
+}
+
+func TestGenerateJSONReferenceCycle(t *testing.T) {
+	schema := []byte(`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`)
+	_, err := fake.New(1).GenerateJSON(schema)
+	require.ErrorIs(t, err, validator.ErrReferenceCycle)
+}
+
+func TestGenerateJSONWideIntegerRange(t *testing.T) {
+	schema := []byte(`{"type": "integer", "minimum": -9223372036854775808, "maximum": 9223372036854775807}`)
+	compiled, err := validator.CompileJSON(schema)
+	require.NoError(t, err)
+	generator := fake.New(1)
+	for i := 0; i < 20; i++ {
+		instance, err := generator.GenerateJSON(schema)
+		require.NoError(t, err)
+		require.NoError(t, compiled.ValidateBytes(instance), string(instance))
+	}
+}
+
+func TestGenerateJSONIntegerSubsetOfNumber(t *testing.T) {
+	for _, schema := range []string{
+		`{"allOf": [{"type": "number"}, {"type": "integer"}]}`,
+		`{"allOf": [{"type": "integer", "minimum": 1}, {"type": ["number", "string"], "maximum": 3}]}`,
+		`{"$ref": "#/$defs/Amount", "type": "integer", "$defs": {"Amount": {"type": "number", "minimum": 0}}}`,
+	} {
+		compiled, err := validator.CompileJSON([]byte(schema))
+		require.NoError(t, err)
+		generator := fake.New(1)
+		for i := 0; i < 10; i++ {
+			instance, err := generator.GenerateJSON([]byte(schema))
+			require.NoError(t, err, schema)
+			require.NoError(t, compiled.ValidateBytes(instance), string(instance))
+			var value any
+			require.NoError(t, json.Unmarshal(instance, &value))
+			require.Equal(t, math.Trunc(value.(float64)), value.(float64), schema)
+		}
+	}
+}
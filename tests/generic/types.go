@@ -0,0 +1,18 @@
+package generic
+
+import "github.com/paulrozhkin/jsonschema/tests/additional"
+
+type Page[T any] struct {
+	Item  T   `json:"item"`
+	Total int `json:"total"`
+}
+
+type Result[T any] struct {
+	Value *T  `json:"value,omitempty"`
+	Code  int `json:"code"`
+}
+
+type Response struct {
+	Settings Page[additional.InnerSettings] `json:"settings"`
+	Count    Result[int]                    `json:"count"`
+}
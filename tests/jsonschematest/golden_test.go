@@ -0,0 +1,82 @@
+package jsonschematest
+
+import (
+	"flag"
+	"github.com/paulrozhkin/jsonschema/pkg/jsonschematest"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// update is the usual flag of golden files, that test packages declare themselves
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestAssertSchemaGolden(t *testing.T) {
+	jsonschematest.AssertSchemaGolden(t, base.Settings{}, "../output/settings.json")
+}
+
+func TestCheckGolden(t *testing.T) {
+	setUpdate(t, false)
+	filename := filepath.Join(t.TempDir(), "user.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{
+  "type": "object",
+  "properties": {"name": {"type": "string"}, "tags": {"enum": ["a", "b"]}},
+  "required": ["name"]
+}
+`), 0o644))
+
+	require.NoError(t, jsonschematest.CheckGolden(
+		[]byte(`{"type":"object","properties":{"name":{"type":"string"},"tags":{"enum":["a","b"]}},"required":["name"]}`),
+		filename))
+
+	err := jsonschematest.CheckGolden([]byte(`{"type": "object", "properties": {"name": {"type": "integer"},
+		"tags": {"enum": ["a"]}, "age/years": {}}}`), filename)
+	require.ErrorIs(t, err, jsonschematest.ErrGoldenMismatch)
+	require.EqualError(t, err, "output differs from golden file "+filename+
+		", run test with -jsonschematest.update to rewrite it:\n"+
+		"#/properties/age~1years: unexpected {}\n"+
+		"#/properties/name/type: expected \"string\", actual \"integer\"\n"+
+		"#/properties/tags/enum/1: missing, expected \"b\"\n"+
+		"#/required: missing, expected [\"name\"]")
+
+	err = jsonschematest.CheckGolden([]byte(`{"properties": {"name": {"type": "string"}, "tags": {"enum": ["a", "b"]}},
+		"type": "object", "required": ["name"]}`), filename)
+	require.EqualError(t, err, "output differs from golden file "+filename+
+		", run test with -jsonschematest.update to rewrite it:\n"+
+		"order of keys differs")
+}
+
+func TestCheckGoldenUpdate(t *testing.T) {
+	setUpdate(t, false)
+	filename := filepath.Join(t.TempDir(), "schemas", "user.json")
+	require.ErrorIs(t, jsonschematest.CheckGolden([]byte(`{}`), filename), os.ErrNotExist)
+
+	setUpdate(t, true)
+	require.NoError(t, jsonschematest.CheckGolden([]byte(`{"type": "object"}`), filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "{\"type\": \"object\"}\n", string(data))
+
+	// -update flag of test package does not rewrite golden files
+	setUpdate(t, false)
+	*update = true
+	require.ErrorIs(t, jsonschematest.CheckGolden([]byte(`{"type": "string"}`), filename),
+		jsonschematest.ErrGoldenMismatch)
+	data, err = os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "{\"type\": \"object\"}\n", string(data))
+}
+
+// setUpdate sets -jsonschematest.update flag and resets -update flag of test package
+func setUpdate(t *testing.T, value bool) {
+	previous, previousTest := flag.Lookup("jsonschematest.update").Value.String(), *update
+	require.NoError(t, flag.Set("jsonschematest.update", strconv.FormatBool(value)))
+	*update = false
+	t.Cleanup(func() {
+		require.NoError(t, flag.Set("jsonschematest.update", previous))
+		*update = previousTest
+	})
+}
@@ -0,0 +1,41 @@
+package jsonschematest
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema/pkg/fake"
+	"github.com/paulrozhkin/jsonschema/pkg/jsonschematest"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type Renamed struct {
+	Name string `json:"name"`
+}
+
+// MarshalJSON writes name under other property, that schema does not know
+func (r Renamed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"title": r.Name})
+}
+
+type Overflow struct {
+	Level int8 `json:"level" jsonschema:"minimum=900,maximum=1000"`
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	jsonschematest.AssertRoundTrip(t, base.Settings{})
+	jsonschematest.AssertRoundTrip(t, &base.Account{}, jsonschematest.WithSeed(7), jsonschematest.WithCount(10),
+		jsonschematest.WithFakeOptions(fake.WithOptionalProbability(1)))
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	err := jsonschematest.CheckRoundTrip(Renamed{})
+	require.ErrorIs(t, err, jsonschematest.ErrRoundTrip)
+	require.ErrorContains(t, err, "is marshalled to {\"title\"")
+
+	// Bounds of tag do not fit into Go type
+	err = jsonschematest.CheckRoundTrip(Overflow{})
+	require.ErrorIs(t, err, jsonschematest.ErrRoundTrip)
+	require.ErrorContains(t, err, "cannot unmarshal number")
+	require.Error(t, jsonschematest.CheckRoundTrip(nil))
+}
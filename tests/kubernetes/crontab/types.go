@@ -0,0 +1,35 @@
+package crontab
+
+import "encoding/json"
+
+type CronTab struct {
+	Spec   CronTabSpec    `json:"spec"`
+	Status *CronTabStatus `json:"status,omitempty"`
+}
+
+type CronTabSpec struct {
+	CronSpec   string            `json:"cronSpec" jsonschema:"minLength=1"`
+	Replicas   *int              `json:"replicas,omitempty" jsonschema:"minimum=1,exclusiveMaximum=10"`
+	Port       any               `json:"port" kubernetes:"intOrString"`
+	Containers []Container       `json:"containers" kubernetes:"listType=map,listMapKey=name"`
+	Args       []string          `json:"args,omitempty" kubernetes:"listType=atomic"`
+	Selector   map[string]string `json:"selector,omitempty" kubernetes:"mapType=atomic"`
+	Config     json.RawMessage   `json:"config,omitempty"`
+	Template   map[string]any    `json:"template,omitempty" kubernetes:"embeddedResource,preserveUnknownFields"`
+	Key        []byte            `json:"key,omitempty"`
+}
+
+type Container struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+type CronTabStatus struct {
+	LastScheduleTime *string  `json:"lastScheduleTime,omitempty"`
+	Active           []string `json:"active,omitempty" kubernetes:"listType=set"`
+}
+
+type Node struct {
+	Name     string `json:"name"`
+	Children []Node `json:"children"`
+}
@@ -0,0 +1,59 @@
+package kubernetes
+
+import (
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/kubernetes"
+	"github.com/paulrozhkin/jsonschema/tests/kubernetes/crontab"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+)
+
+func TestFromTypeToCRD(t *testing.T) {
+	expected, err := os.ReadFile("crontab.yaml")
+	require.NoError(t, err)
+
+	actual, err := jsonschema.FromTypeToCRD(crontab.CronTab{}, kubernetes.Config{
+		Group:      "stable.example.com",
+		ShortNames: []string{"ct"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(actual))
+}
+
+func TestCRDNames(t *testing.T) {
+	crd, err := kubernetes.NewCustomResourceDefinition(kubernetes.Config{
+		Group:   "example.com",
+		Kind:    "Policy",
+		Version: "v1beta1",
+		Plural:  "policies",
+		Scope:   kubernetes.ScopeCluster,
+	}, entity.NewOrderedMap[any]().Set("type", "object"))
+	require.NoError(t, err)
+	require.Equal(t, "policies.example.com", crd.Metadata.Name)
+	require.Equal(t, kubernetes.CustomResourceDefinitionNames{
+		Kind:     "Policy",
+		ListKind: "PolicyList",
+		Plural:   "policies",
+		Singular: "policy",
+	}, crd.Spec.Names)
+	require.Equal(t, kubernetes.ScopeCluster, crd.Spec.Scope)
+	require.Equal(t, "v1beta1", crd.Spec.Versions[0].Name)
+
+	_, err = kubernetes.NewCustomResourceDefinition(kubernetes.Config{Kind: "Policy"}, nil)
+	require.ErrorIs(t, err, kubernetes.ErrGroupNotFound)
+}
+
+func TestCRDRecursiveType(t *testing.T) {
+	_, err := jsonschema.FromTypeToCRD(crontab.Node{}, kubernetes.Config{Group: "example.com"})
+	require.ErrorIs(t, err, kubernetes.ErrRecursiveType)
+}
+
+func TestCRDInvalidMarker(t *testing.T) {
+	type Resource struct {
+		Name string `json:"name" kubernetes:"listType=set"`
+	}
+	_, err := jsonschema.FromTypeToCRD(Resource{}, kubernetes.Config{Group: "example.com"})
+	require.ErrorContains(t, err, "listType=set is allowed only for slices")
+}
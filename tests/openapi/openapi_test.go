@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/openapi"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestComponentsFromPackage(t *testing.T) {
+	for _, version := range []openapi.Version{openapi.Version30, openapi.Version31} {
+		t.Run(string(version), func(t *testing.T) {
+			components, err := jsonschema.FromPackageToOpenAPIComponents("github.com/paulrozhkin/jsonschema/tests/base", version)
+			require.NoError(t, err)
+			actual, err := json.Marshal(components)
+			require.NoError(t, err)
+			require.JSONEq(t, `{
+				"schemas": {
+					"Account": {
+						"type": "object",
+						"properties": {
+							"name": {"type": "string"},
+							"settings": {"$ref": "#/components/schemas/InnerSettings"}
+						},
+						"required": ["name", "settings"]
+					},
+					"Settings": {
+						"type": "object",
+						"properties": {
+							"valInnerSettings": {"$ref": "#/components/schemas/InnerSettings"},
+							"refInnerSettings": {"$ref": "#/components/schemas/InnerSettings"},
+							"floatValue": {"type": "number"}
+						},
+						"required": ["valInnerSettings", "floatValue"]
+					},
+					"InnerSettings": {
+						"type": "object",
+						"properties": {
+							"stringValue": {"type": "string"},
+							"intValue": {"type": "integer", "minimum": 0, "maximum": 10},
+							"boolValue": {"type": "boolean"}
+						},
+						"required": ["stringValue", "intValue", "boolValue"]
+					}
+				}
+			}`, string(actual))
+		})
+	}
+}
+
+func TestComponentsFromType(t *testing.T) {
+	generator, err := jsonschema.FromTypeToJsonSchema(base.Settings{})
+	require.NoError(t, err)
+	components, err := generator.GenerateOpenAPIComponents(openapi.Version31)
+	require.NoError(t, err)
+	require.Equal(t, []string{"InnerSettings", "Settings"}, components.Schemas.Keys())
+
+	// Properties keep order of struct fields
+	settings, _ := components.Schemas.Get("Settings")
+	actual, err := json.Marshal(settings)
+	require.NoError(t, err)
+	require.Equal(t, `{"type":"object","properties":{`+
+		`"valInnerSettings":{"$ref":"#/components/schemas/InnerSettings"},`+
+		`"refInnerSettings":{"$ref":"#/components/schemas/InnerSettings"},`+
+		`"floatValue":{"type":"number"}},`+
+		`"required":["valInnerSettings","floatValue"]}`, string(actual))
+}
+
+func TestOpenAPI30Dialect(t *testing.T) {
+	var bundle entity.JSONSchema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/api",
+		"$defs": {
+			"Item": {
+				"type": "object",
+				"$comment": "generated",
+				"properties": {
+					"name": {"type": ["string", "null"]},
+					"code": {"type": "string", "examples": ["pen", "pencil"]},
+					"price": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 100.5},
+					"kind": {"type": "string", "const": "item"},
+					"tags": {"type": "array", "items": {"$ref": "#/$defs/Tag"}}
+				}
+			},
+			"Tag": {"type": "string"}
+		}
+	}`), &bundle))
+
+	components, err := openapi.FromBundle(&bundle, openapi.Version30)
+	require.NoError(t, err)
+	actual, err := json.Marshal(components)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schemas": {
+			"Item": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "nullable": true},
+					"code": {"type": "string", "example": "pen"},
+					"price": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 100.5, "exclusiveMaximum": true},
+					"kind": {"type": "string", "enum": ["item"]},
+					"tags": {"type": "array", "items": {"$ref": "#/components/schemas/Tag"}}
+				}
+			},
+			"Tag": {"type": "string"}
+		}
+	}`, string(actual))
+
+	components, err = openapi.FromBundle(&bundle, openapi.Version31)
+	require.NoError(t, err)
+	item, _ := components.Schemas.Get("Item")
+	actual, err = json.Marshal(item)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"$comment": "generated",
+		"properties": {
+			"name": {"type": ["string", "null"]},
+			"code": {"type": "string", "examples": ["pen", "pencil"]},
+			"price": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 100.5},
+			"kind": {"type": "string", "const": "item"},
+			"tags": {"type": "array", "items": {"$ref": "#/components/schemas/Tag"}}
+		}
+	}`, string(actual))
+}
+
+func TestOpenAPI30TighterBound(t *testing.T) {
+	var bundle entity.JSONSchema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"$defs": {
+			"Inclusive": {"type": "number", "minimum": 10, "exclusiveMinimum": 5, "maximum": 3, "exclusiveMaximum": 5},
+			"Exclusive": {"type": "number", "minimum": 5, "exclusiveMinimum": 5, "maximum": 20, "exclusiveMaximum": 15}
+		}
+	}`), &bundle))
+
+	components, err := openapi.FromBundle(&bundle, openapi.Version30)
+	require.NoError(t, err)
+	actual, err := json.Marshal(components)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schemas": {
+			"Inclusive": {"type": "number", "minimum": 10, "maximum": 3},
+			"Exclusive": {"type": "number", "minimum": 5, "exclusiveMinimum": true, "maximum": 15, "exclusiveMaximum": true}
+		}
+	}`, string(actual))
+}
+
+func TestOpenAPI30UnsupportedKeyword(t *testing.T) {
+	bundle := entity.NewJSONEmptySchema().AddDefinition("Item", &entity.ArraySchema{PrefixItems: []*entity.JSONSchema{{}}})
+	_, err := openapi.FromBundle(bundle, openapi.Version30)
+	require.ErrorContains(t, err, "keyword prefixItems is not supported by OpenAPI 3.0 at /Item")
+
+	_, err = openapi.FromBundle(bundle, "2.0")
+	require.ErrorIs(t, err, openapi.ErrUnknownVersion)
+}
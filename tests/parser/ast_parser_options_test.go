@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+const taggedPackage = "github.com/paulrozhkin/jsonschema/tests/tagged"
+
+func TestAstBuildTags(t *testing.T) {
+	_, err := parser.NewAstParser("Extra", taggedPackage).Parse()
+	require.ErrorContains(t, err, "struct Extra does not exist")
+
+	result, err := parser.NewAstParser("Extra", taggedPackage, parser.WithBuildTags("jsonschema_extra")).Parse()
+	require.NoError(t, err)
+	require.Equal(t, "Extra", result.Root.TypeName)
+}
+
+func TestAstEnv(t *testing.T) {
+	result, err := parser.NewAstParser("WindowsOnly", taggedPackage, parser.WithEnv("GOOS=windows")).Parse()
+	require.NoError(t, err)
+	require.Equal(t, "WindowsOnly", result.Root.TypeName)
+}
+
+func TestAstDir(t *testing.T) {
+	result, err := parser.NewAstParser("Settings", ".", parser.WithDir("../base")).Parse()
+	require.NoError(t, err)
+	require.Equal(t, "github.com/paulrozhkin/jsonschema/tests/base", result.Root.Package)
+}
+
+func TestAstOverlay(t *testing.T) {
+	filename, err := filepath.Abs("../tagged/types_overlay.go")
+	require.NoError(t, err)
+	overlay := map[string][]byte{
+		filename: []byte("package tagged\n\ntype Overlay struct {\n\tID int `json:\"id\"`\n}\n"),
+	}
+
+	result, err := parser.NewAstParser("Overlay", taggedPackage, parser.WithOverlay(overlay)).Parse()
+	require.NoError(t, err)
+	require.Equal(t, "Overlay", result.Root.TypeName)
+	require.Equal(t, "ID", result.Root.Nodes[0].FieldName)
+}
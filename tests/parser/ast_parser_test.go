@@ -1,9 +1,14 @@
 package parser
 
 import (
+	"github.com/paulrozhkin/jsonschema/pkg/converter"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
 	"github.com/paulrozhkin/jsonschema/pkg/parser"
 	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/paulrozhkin/jsonschema/tests/kubernetes/crontab"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
 	"testing"
 )
 
@@ -14,3 +19,163 @@ func TestAstFromPackageAndTypeNames(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expectedMetadata, result)
 }
+
+func TestAstAllExportedStructsOfPackage(t *testing.T) {
+	astParser := parser.NewAstParserForTypes(nil, "github.com/paulrozhkin/jsonschema/tests/base")
+	result, err := astParser.Parse()
+	require.NoError(t, err)
+	require.Nil(t, result.Root)
+	require.Len(t, result.Roots, 2)
+	require.Equal(t, "Account", result.Roots[0].TypeName)
+	require.Equal(t, "Settings", result.Roots[1].TypeName)
+	require.Len(t, result.Types, 3)
+
+	// Both roots share the same definition of InnerSettings
+	require.Same(t, result.Roots[0].Nodes[1].Ref, result.Roots[1].Nodes[0].Ref)
+}
+
+func TestAstPatternWithFilter(t *testing.T) {
+	const pattern = "github.com/paulrozhkin/jsonschema/tests/batch/..."
+	tests := []struct {
+		name     string
+		filter   *parser.TypeFilter
+		expected []string
+	}{
+		{
+			name:     "All exported structs",
+			filter:   nil,
+			expected: []string{"User", "UserRequest", "UserResponse", "Order", "OrderRequest"},
+		},
+		{
+			name:     "Name pattern",
+			filter:   &parser.TypeFilter{NamePattern: regexp.MustCompile("Request$")},
+			expected: []string{"UserRequest", "OrderRequest"},
+		},
+		{
+			name:     "Marker comment",
+			filter:   &parser.TypeFilter{Marker: "jsonschema:generate"},
+			expected: []string{"User", "Order"},
+		},
+		{
+			name:     "Implemented interface",
+			filter:   &parser.TypeFilter{Implements: "fmt.Stringer"},
+			expected: []string{"UserRequest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parser.NewAstPatternParser([]string{pattern}, tt.filter).Parse()
+			require.NoError(t, err)
+			var names []string
+			for _, root := range result.RootTypes() {
+				names = append(names, root.TypeName)
+			}
+			require.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
+
+func TestAstFromSources(t *testing.T) {
+	sources := map[string][]byte{
+		"settings.go": []byte(`package snippet
+
+type Settings struct {
+	Inner *Inner ` + "`json:\"inner,omitempty\"`" + `
+	Count int    ` + "`json:\"count\" jsonschema:\"minimum=1\"`" + `
+}
+`),
+		"inner.go": []byte(`package snippet
+
+import "time"
+
+type Inner struct {
+	Name    string ` + "`json:\"name\"`" + `
+	Timeout time.Duration
+}
+`),
+	}
+
+	result, err := parser.NewAstSourceParser([]string{"Settings"}, "example.com/snippet", sources).Parse()
+	require.NoError(t, err)
+	require.Equal(t, "example.com/snippet", result.Root.Package)
+	require.Equal(t, "Inner", result.Root.Nodes[0].Ref.TypeName)
+	require.True(t, result.Root.Nodes[0].IsPointer)
+	require.Equal(t, []string{"minimum=1"}, result.Root.Nodes[1].Tags["jsonschema"])
+	require.Len(t, result.Types, 2)
+
+	timeout := result.Root.Nodes[0].Ref.Nodes[1]
+	require.Equal(t, "int64", timeout.TypeKind)
+	require.Nil(t, timeout.Tags)
+}
+
+func TestAstFromSourcesTypeError(t *testing.T) {
+	sources := map[string][]byte{
+		"broken.go": []byte("package snippet\n\ntype Broken struct {\n\tValue Unknown\n}\n"),
+	}
+
+	_, err := parser.NewAstSourceParser([]string{"Broken"}, "example.com/snippet", sources).Parse()
+	require.ErrorContains(t, err, "undefined: Unknown")
+}
+
+func TestAstCollections(t *testing.T) {
+	cfg := entity.Config{SchemaVersion: entity.Draft202012}
+	schemaConverter := converter.NewMetaToSchemaConverter()
+
+	astMetadata, err := parser.NewAstParser("CronTab", "github.com/paulrozhkin/jsonschema/tests/kubernetes/crontab").Parse()
+	require.NoError(t, err)
+	astSchema, err := schemaConverter.Convert(cfg, astMetadata)
+	require.NoError(t, err)
+
+	// Slices, maps, any and json.RawMessage are parsed like reflect parser does
+	reflectMetadata, err := parser.NewReflectParser(crontab.CronTab{}).Parse()
+	require.NoError(t, err)
+	reflectSchema, err := schemaConverter.Convert(cfg, reflectMetadata)
+	require.NoError(t, err)
+	require.Equal(t, reflectSchema, astSchema)
+
+	spec := astMetadata.Types["github.com/paulrozhkin/jsonschema/tests/kubernetes/crontab#CronTabSpec"]
+	require.Equal(t, "slice", spec.Nodes[3].TypeKind)
+	require.Equal(t, "Container", spec.Nodes[3].Nodes[0].Ref.TypeName)
+	// json.RawMessage is alias of jsontext.Value with jsonv2 experiment
+	require.Contains(t, []string{"encoding/json#RawMessage", "encoding/json/jsontext#Value"}, spec.Nodes[6].ID())
+}
+
+func TestAstCollectionsFromSources(t *testing.T) {
+	sources := map[string][]byte{
+		"holder.go": []byte(`package snippet
+
+type Level int8
+
+type Alias = Item
+
+type Item struct {
+	Name string
+}
+
+type Holder struct {
+	Items  []Alias
+	Fixed  [3]float32
+	Lookup map[string]*Item
+	Levels []Level
+	Any    interface{}
+}
+`),
+	}
+
+	result, err := parser.NewAstSourceParser([]string{"Holder"}, "example.com/snippet", sources).Parse()
+	require.NoError(t, err)
+	nodes := result.Root.Nodes
+
+	require.Equal(t, "slice", nodes[0].TypeKind)
+	require.Equal(t, "Item", nodes[0].Nodes[0].Ref.TypeName)
+	require.Equal(t, "array", nodes[1].TypeKind)
+	require.Equal(t, "float32", nodes[1].Nodes[0].TypeKind)
+	require.Equal(t, "map", nodes[2].TypeKind)
+	require.Equal(t, "Item", nodes[2].Nodes[0].Ref.TypeName)
+	require.True(t, nodes[2].Nodes[0].IsPointer)
+	// Named basic type is parsed as its underlying kind
+	require.Equal(t, "int8", nodes[3].Nodes[0].TypeKind)
+	require.Equal(t, "interface", nodes[4].TypeKind)
+	require.Len(t, result.Types, 2)
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"github.com/paulrozhkin/jsonschema/tests/generic"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"testing"
+)
+
+const genericPackage = "github.com/paulrozhkin/jsonschema/tests/generic"
+
+func TestReflectGenericInstantiations(t *testing.T) {
+	result, err := parser.NewReflectParser(generic.Response{}).Parse()
+	require.NoError(t, err)
+	require.Equal(t, []string{"InnerSettings", "PageOfInnerSettings", "Response", "ResultOfInt"}, typeNames(result))
+}
+
+func TestAstGenericInstantiations(t *testing.T) {
+	result, err := parser.NewAstParser("Response", genericPackage).Parse()
+	require.NoError(t, err)
+	require.Equal(t, []string{"InnerSettings", "PageOfInnerSettings", "Response", "ResultOfInt"}, typeNames(result))
+
+	reflectResult, err := parser.NewReflectParser(generic.Response{}).Parse()
+	require.NoError(t, err)
+	require.Equal(t, reflectResult, result)
+}
+
+func TestAstGenericRootInstantiation(t *testing.T) {
+	result, err := parser.NewAstParser("Page[additional.InnerSettings]", genericPackage).Parse()
+	require.NoError(t, err)
+	require.Equal(t, "PageOfInnerSettings", result.Root.TypeName)
+	require.Equal(t, "InnerSettings", result.Root.Nodes[0].Ref.TypeName)
+}
+
+func TestAstGenericUnresolvedTypeParameter(t *testing.T) {
+	_, err := parser.NewAstParser("Page", genericPackage).Parse()
+	require.ErrorContains(t, err, "unresolved type parameters [T]")
+}
+
+func typeNames(metadata *entity.JsonSchemaMetadata) []string {
+	names := make([]string, 0, len(metadata.Types))
+	for _, dataTypeMetadata := range metadata.Types {
+		names = append(names, dataTypeMetadata.TypeName)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,101 @@
+package registry
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	schemaRegistry, err := registry.New(t.TempDir())
+	require.NoError(t, err)
+	server := httptest.NewServer(registry.NewHandler(schemaRegistry))
+	defer server.Close()
+
+	schemaRequest := func(schema string) string {
+		data, err := json.Marshal(map[string]string{"schema": schema, "schemaType": "JSON"})
+		require.NoError(t, err)
+		return string(data)
+	}
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		status   int
+		expected string
+	}{
+		{http.MethodPost, "/subjects/orders-value/versions", schemaRequest(orderV1), http.StatusOK, `{"id": 1}`},
+		{http.MethodPost, "/subjects/orders-value/versions", schemaRequest(orderV2), http.StatusOK, `{"id": 2}`},
+		{http.MethodGet, "/subjects", "", http.StatusOK, `["orders-value"]`},
+		{http.MethodGet, "/subjects/orders-value/versions", "", http.StatusOK, `[1, 2]`},
+		{http.MethodGet, "/subjects/orders-value/versions/latest", "", http.StatusOK, `{"subject": "orders-value",
+			"version": 2, "id": 2, "schemaType": "JSON", "schema": ` + string(canonicalString(t, orderV2)) + `}`},
+		{http.MethodGet, "/subjects/orders-value/versions/1/schema", "", http.StatusOK, orderV1},
+		{http.MethodGet, "/schemas/ids/1", "", http.StatusOK, `{"schema": ` + string(canonicalString(t, orderV1)) +
+			`, "schemaType": "JSON"}`},
+		{http.MethodGet, "/schemas/ids/1/schema", "", http.StatusOK, orderV1},
+		{http.MethodPost, "/subjects/orders-value", schemaRequest(orderV1), http.StatusOK, `{"subject": "orders-value",
+			"version": 1, "id": 1, "schemaType": "JSON", "schema": ` + string(canonicalString(t, orderV1)) + `}`},
+		{http.MethodPost, "/compatibility/subjects/orders-value/versions/latest", schemaRequest(orderV3),
+			http.StatusOK, `{"is_compatible": false}`},
+		{http.MethodPost, "/compatibility/subjects/orders-value/versions?verbose=true", schemaRequest(orderV3),
+			http.StatusOK, `{"is_compatible": false, "messages": ["schema is incompatible with previous versions: ` +
+				`schema is not BACKWARD compatible with version 2: #/required: property \"note\" is required"]}`},
+		{http.MethodPost, "/subjects/orders-value/versions", schemaRequest(orderV3), http.StatusConflict, ""},
+		{http.MethodGet, "/config", "", http.StatusOK, `{"compatibilityLevel": "BACKWARD"}`},
+		{http.MethodPut, "/config/orders-value", `{"compatibility": "NONE"}`, http.StatusOK, `{"compatibility": "NONE"}`},
+		{http.MethodGet, "/config/orders-value", "", http.StatusOK, `{"compatibilityLevel": "NONE"}`},
+		{http.MethodPost, "/subjects/orders-value/versions", schemaRequest(orderV3), http.StatusOK, `{"id": 3}`},
+		{http.MethodPut, "/config", `{"compatibility": "STRICT"}`, http.StatusUnprocessableEntity, ""},
+		{http.MethodGet, "/subjects/payments-value/versions/1", "", http.StatusNotFound, ""},
+		{http.MethodGet, "/subjects/orders-value/versions/4", "", http.StatusNotFound, ""},
+		{http.MethodGet, "/subjects/orders-value/versions/first", "", http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/subjects/orders-value/versions", `{"schema": "{}", "schemaType": "AVRO"}`,
+			http.StatusUnprocessableEntity, ""},
+		// Schema with cycle of references can not be compared with previous version
+		{http.MethodPost, "/subjects/cycle-value/versions", schemaRequest(`{"type": "string"}`), http.StatusOK,
+			`{"id": 4}`},
+		{http.MethodPost, "/subjects/cycle-value/versions", schemaRequest(`{"$ref": "#"}`),
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/compatibility/subjects/cycle-value/versions/latest", schemaRequest(`{"$ref": "#"}`),
+			http.StatusUnprocessableEntity, ""},
+		// Subject .. is not parent directory of subject configs
+		{http.MethodPut, "/config/%2E%2E", `{"compatibility": "NONE"}`, http.StatusOK, `{"compatibility": "NONE"}`},
+		{http.MethodGet, "/config", "", http.StatusOK, `{"compatibilityLevel": "BACKWARD"}`},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+		require.NoError(t, err)
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		require.NoError(t, response.Body.Close())
+
+		require.Equal(t, tt.status, response.StatusCode, "%s %s: %s", tt.method, tt.path, body)
+		require.Equal(t, registry.ContentType, response.Header.Get("Content-Type"))
+		if tt.expected != "" {
+			require.JSONEq(t, tt.expected, string(body), "%s %s", tt.method, tt.path)
+		} else {
+			var errorResponse map[string]any
+			require.NoError(t, json.Unmarshal(body, &errorResponse))
+			require.Contains(t, errorResponse, "error_code")
+		}
+	}
+}
+
+// canonicalString returns canonical form of schema as JSON string
+func canonicalString(t *testing.T, schema string) []byte {
+	var document any
+	require.NoError(t, json.Unmarshal([]byte(schema), &document))
+	canonical, err := json.Marshal(document)
+	require.NoError(t, err)
+	data, err := json.Marshal(string(canonical))
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,125 @@
+package registry
+
+import (
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/compatibility"
+	"github.com/paulrozhkin/jsonschema/pkg/registry"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const (
+	orderV1 = `{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`
+	orderV2 = `{"type": "object", "properties": {"id": {"type": "string"}, "note": {}}, "required": ["id"]}`
+	orderV3 = `{"type": "object", "properties": {"id": {"type": "string"}, "note": {}}, "required": ["id", "note"]}`
+)
+
+func TestRegister(t *testing.T) {
+	schemaRegistry, err := registry.New(t.TempDir())
+	require.NoError(t, err)
+
+	first, err := schemaRegistry.Register("orders-value", []byte(orderV1))
+	require.NoError(t, err)
+	require.Equal(t, &registry.Schema{Subject: "orders-value", Version: 1, ID: 1, SchemaType: registry.SchemaTypeJSON,
+		Schema: `{"properties":{"id":{"type":"string"}},"required":["id"],"type":"object"}`}, first)
+	second, err := schemaRegistry.Register("orders-value", []byte(orderV2))
+	require.NoError(t, err)
+	require.Equal(t, 2, second.Version)
+	require.Equal(t, 2, second.ID)
+
+	// Equal schema is registered once in subject and has the same id in all subjects
+	again, err := schemaRegistry.Register("orders-value", []byte(orderV1))
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+	other, err := schemaRegistry.Register("orders/archive", []byte(orderV1))
+	require.NoError(t, err)
+	require.Equal(t, 1, other.Version)
+	require.Equal(t, 1, other.ID)
+
+	latest, err := schemaRegistry.Latest("orders-value")
+	require.NoError(t, err)
+	require.Equal(t, second, latest)
+	version, err := schemaRegistry.Get("orders-value", 1)
+	require.NoError(t, err)
+	require.Equal(t, first, version)
+	versions, err := schemaRegistry.Versions("orders-value")
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, versions)
+	subjects, err := schemaRegistry.Subjects()
+	require.NoError(t, err)
+	require.Equal(t, []string{"orders-value", "orders/archive"}, subjects)
+	schema, err := schemaRegistry.SchemaByID(2)
+	require.NoError(t, err)
+	require.JSONEq(t, orderV2, string(schema))
+
+	_, err = schemaRegistry.Latest("payments-value")
+	require.ErrorIs(t, err, registry.ErrSubjectNotFound)
+	_, err = schemaRegistry.Get("orders-value", 3)
+	require.ErrorIs(t, err, registry.ErrVersionNotFound)
+	_, err = schemaRegistry.SchemaByID(3)
+	require.ErrorIs(t, err, registry.ErrSchemaNotFound)
+	_, err = schemaRegistry.Register("orders-value", []byte(`[]`))
+	require.ErrorIs(t, err, registry.ErrInvalidSchema)
+}
+
+func TestRegisterIncompatible(t *testing.T) {
+	schemaRegistry, err := registry.New(t.TempDir())
+	require.NoError(t, err)
+	_, err = schemaRegistry.Register("orders-value", []byte(orderV2))
+	require.NoError(t, err)
+
+	// New required property rejects data of previous version
+	_, err = schemaRegistry.Register("orders-value", []byte(orderV3))
+	require.ErrorIs(t, err, registry.ErrIncompatibleSchema)
+	var incompatibleErr *compatibility.IncompatibleError
+	require.ErrorAs(t, err, &incompatibleErr)
+	require.Equal(t, 1, incompatibleErr.Version)
+
+	require.NoError(t, schemaRegistry.SetCompatibility("orders-value", compatibility.Forward))
+	level, err := schemaRegistry.Compatibility("orders-value")
+	require.NoError(t, err)
+	require.Equal(t, compatibility.Forward, level)
+	level, err = schemaRegistry.Compatibility("")
+	require.NoError(t, err)
+	require.Equal(t, compatibility.Backward, level)
+
+	require.NoError(t, schemaRegistry.CheckCompatibility("orders-value", []byte(orderV3)))
+	registered, err := schemaRegistry.Register("orders-value", []byte(orderV3))
+	require.NoError(t, err)
+	require.Equal(t, 2, registered.Version)
+}
+
+func TestRegisterDotSubjects(t *testing.T) {
+	schemaRegistry, err := registry.New(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, schemaRegistry.SetCompatibility("..", compatibility.None))
+	level, err := schemaRegistry.Compatibility("")
+	require.NoError(t, err)
+	require.Equal(t, compatibility.Backward, level)
+
+	for _, subject := range []string{".", "..", "com.example.Order-value"} {
+		registered, err := schemaRegistry.Register(subject, []byte(orderV1))
+		require.NoError(t, err)
+		require.Equal(t, 1, registered.Version)
+	}
+	subjects, err := schemaRegistry.Subjects()
+	require.NoError(t, err)
+	require.Equal(t, []string{".", "..", "com.example.Order-value"}, subjects)
+}
+
+func TestRegisterGeneratedSchema(t *testing.T) {
+	schemaRegistry, err := registry.New(t.TempDir())
+	require.NoError(t, err)
+	generator, err := jsonschema.FromTypeToJsonSchema(base.Settings{})
+	require.NoError(t, err)
+
+	registered, err := registry.RegisterGenerator(schemaRegistry, generator, "settings-value")
+	require.NoError(t, err)
+	require.Equal(t, 1, registered.Version)
+	// Service registers the same schema at every startup
+	registered, err = registry.RegisterGenerator(schemaRegistry, generator, "settings-value")
+	require.NoError(t, err)
+	require.Equal(t, 1, registered.Version)
+}
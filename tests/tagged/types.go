@@ -0,0 +1,5 @@
+package tagged
+
+type Common struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,7 @@
+//go:build jsonschema_extra
+
+package tagged
+
+type Extra struct {
+	Value int `json:"value"`
+}
@@ -0,0 +1,5 @@
+package tagged
+
+type WindowsOnly struct {
+	Path string `json:"path"`
+}
@@ -0,0 +1,164 @@
+package transform
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema/pkg/transform"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundleFile(t *testing.T) {
+	schema, err := transform.BundleFile("bundle/order.json")
+	require.NoError(t, err)
+
+	actual, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"total": {"$ref": "#/$defs/common/$defs/Money"},
+			"address": {"$ref": "#/$defs/address"},
+			"lines": {"type": "array", "items": {"$ref": "#/$defs/Line"}}
+		},
+		"required": ["total"],
+		"$defs": {
+			"Line": {
+				"type": "object",
+				"properties": {
+					"price": {"$ref": "#/$defs/common/$defs/Money"}
+				}
+			},
+			"common": {
+				"$defs": {
+					"Money": {
+						"type": "object",
+						"properties": {
+							"amount": {"type": "number"},
+							"currency": {"$ref": "#/$defs/common/$defs/Currency"}
+						},
+						"required": ["amount", "currency"]
+					},
+					"Currency": {"type": "string", "pattern": "^[A-Z]{3}$"}
+				}
+			},
+			"address": {
+				"type": "object",
+				"properties": {
+					"city": {"type": "string"},
+					"country": {"$ref": "#/$defs/common/$defs/Currency"}
+				}
+			}
+		}
+	}`, string(actual))
+
+	// Bundle is self-contained
+	compiled, err := validator.Compile(schema)
+	require.NoError(t, err)
+	require.NoError(t, compiled.ValidateBytes([]byte(`{"total": {"amount": 10, "currency": "EUR"}, "address": {"country": "DEU"}}`)))
+	require.Error(t, compiled.ValidateBytes([]byte(`{"total": {"amount": 10, "currency": "euro"}}`)))
+}
+
+func TestBundleByID(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schemas/user.json": {Data: []byte(`{
+			"$id": "https://example.com/schemas/user.json",
+			"type": "object",
+			"properties": {
+				"id": {"$ref": "https://example.com/schemas/id.json"},
+				"email": {"$ref": "https://example.org/email.json"}
+			},
+			"$defs": {"id": {"type": "object"}}
+		}`)},
+		"schemas/id.json": {Data: []byte(`{"$id": "https://example.com/schemas/id.json", "type": "string", "format": "uuid"}`)},
+	}
+
+	schema, err := transform.Bundle(fsys, "schemas/user.json")
+	require.NoError(t, err)
+	actual, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$id": "https://example.com/schemas/user.json",
+		"type": "object",
+		"properties": {
+			"id": {"$ref": "https://example.com/schemas/id.json"},
+			"email": {"$ref": "https://example.org/email.json"}
+		},
+		"$defs": {
+			"id": {"type": "object"},
+			"id2": {"$id": "https://example.com/schemas/id.json", "type": "string", "format": "uuid"}
+		}
+	}`, string(actual))
+}
+
+func TestBundleDraft07Definitions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"order.json": {Data: []byte(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "https://example.com/order.json",
+			"type": "object",
+			"properties": {"total": {"$ref": "common.json#/definitions/Money"}}
+		}`)},
+		"common.json": {Data: []byte(`{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"definitions": {
+				"Money": {
+					"type": "object",
+					"properties": {"amount": {"type": "number"}, "currency": {"$ref": "#/definitions/Currency"}},
+					"required": ["amount", "currency"]
+				},
+				"Currency": {"type": "string", "pattern": "^[A-Z]{3}$"}
+			}
+		}`)},
+	}
+
+	schema, err := transform.Bundle(fsys, "order.json")
+	require.NoError(t, err)
+	actual, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/order.json",
+		"type": "object",
+		"properties": {"total": {"$ref": "https://example.com/common.json#/definitions/Money"}},
+		"$defs": {
+			"common": {
+				"$id": "https://example.com/common.json",
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"definitions": {
+					"Money": {
+						"type": "object",
+						"properties": {"amount": {"type": "number"}, "currency": {"$ref": "#/definitions/Currency"}},
+						"required": ["amount", "currency"]
+					},
+					"Currency": {"type": "string", "pattern": "^[A-Z]{3}$"}
+				}
+			}
+		}
+	}`, string(actual))
+
+	compiled, err := validator.Compile(schema)
+	require.NoError(t, err)
+	require.NoError(t, compiled.ValidateBytes([]byte(`{"total": {"amount": 10, "currency": "EUR"}}`)))
+	require.Error(t, compiled.ValidateBytes([]byte(`{"total": {"amount": 10, "currency": "euro"}}`)))
+}
+
+func TestBundleUnresolvedPointer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"order.json":  {Data: []byte(`{"properties": {"total": {"$ref": "common.json#/definitions/Money"}}}`)},
+		"common.json": {Data: []byte(`{"$defs": {"Money": {"type": "number"}}}`)},
+	}
+	_, err := transform.Bundle(fsys, "order.json")
+	require.ErrorIs(t, err, transform.ErrDefinitionNotFound)
+	require.ErrorContains(t, err, "/$defs/common/definitions/Money")
+}
+
+func TestBundleFileNotFound(t *testing.T) {
+	fsys := fstest.MapFS{
+		"user.json": {Data: []byte(`{"properties": {"id": {"$ref": "id.json"}}}`)},
+	}
+	_, err := transform.Bundle(fsys, "user.json")
+	require.ErrorContains(t, err, "reference id.json: open id.json")
+}
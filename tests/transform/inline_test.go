@@ -0,0 +1,168 @@
+package transform
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/transform"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestInlineRefs(t *testing.T) {
+	generator, err := jsonschema.FromTypeToJsonSchema(base.Settings{})
+	require.NoError(t, err)
+	generator.AfterConvert = transform.InlineRefs
+	require.NoError(t, generator.Generate())
+
+	actual, err := generator.ToJson()
+	require.NoError(t, err)
+	innerSettings := `{
+		"type": "object",
+		"properties": {
+			"stringValue": {"type": "string"},
+			"intValue": {"type": "integer", "minimum": 0, "maximum": 10},
+			"boolValue": {"type": "boolean"}
+		},
+		"required": ["stringValue", "intValue", "boolValue"]
+	}`
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://github.com/paulrozhkin/jsonschema/tests/base/Settings",
+		"type": "object",
+		"properties": {
+			"valInnerSettings": `+innerSettings+`,
+			"refInnerSettings": `+innerSettings+`,
+			"floatValue": {"type": "number"}
+		},
+		"required": ["valInnerSettings", "floatValue"]
+	}`, string(actual))
+}
+
+type Node struct {
+	Name     string `json:"name"`
+	Children []Node `json:"children"`
+}
+
+type Tree struct {
+	Root Node `json:"root"`
+}
+
+func TestInlineRefsKeepsRecursion(t *testing.T) {
+	generator, err := jsonschema.FromTypeToJsonSchema(Tree{})
+	require.NoError(t, err)
+	generator.AfterConvert = transform.InlineRefs
+	require.NoError(t, generator.Generate())
+
+	actual, err := generator.ToJson()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://github.com/paulrozhkin/jsonschema/tests/transform/Tree",
+		"type": "object",
+		"properties": {
+			"root": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}}
+				},
+				"required": ["name", "children"]
+			}
+		},
+		"required": ["root"],
+		"$defs": {
+			"Node": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}}
+				},
+				"required": ["name", "children"]
+			}
+		}
+	}`, string(actual))
+}
+
+func TestInlineRefsOfDocument(t *testing.T) {
+	var schema entity.JSONSchema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"$ref": "#/$defs/Id"},
+			"ids": {"type": "array", "items": {"$ref": "#/$defs/Id"}},
+			"owner": {"$ref": "#/$defs/User", "description": "Owner of document"},
+			"tags": {"type": "array", "items": {"$ref": "#/$defs/Tag"}}
+		},
+		"$defs": {
+			"Id": {"type": "string", "format": "uuid"},
+			"User": {"type": "object", "properties": {"id": {"$ref": "#/$defs/Id"}}},
+			"Tag": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"Unused": {"type": "object"}
+		}
+	}`), &schema))
+
+	require.NoError(t, transform.InlineRefs(&schema))
+	actual, err := json.Marshal(&schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"ids": {"type": "array", "items": {"$ref": "#/$defs/Id"}},
+			"owner": {
+				"description": "Owner of document",
+				"allOf": [{"type": "object", "properties": {"id": {"type": "string", "format": "uuid"}}}]
+			},
+			"tags": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}}}
+		},
+		"$defs": {
+			"Id": {"type": "string", "format": "uuid"}
+		}
+	}`, string(actual))
+}
+
+func TestInlineRefsNotFound(t *testing.T) {
+	schema := entity.NewJSONSchema()
+	schema.AddProperty("id", entity.NewJSONEmptySchema().SetRef("#/$defs/Id"))
+	require.ErrorIs(t, transform.InlineRefs(schema), transform.ErrDefinitionNotFound)
+}
+
+func TestInlineRootRef(t *testing.T) {
+	const (
+		rootRefSchema = `{
+			"$ref": "#/$defs/Order",
+			"$defs": {
+				"Order": {"type": "object", "properties": {"id": {"$ref": "#/$defs/Id"}, "next": {"$ref": "#/$defs/Order"}}},
+				"Id": {"type": "string"}
+			}
+		}`
+		order = `{"type": "object", "properties": {"id": {"type": "string"}, "next": {"$ref": "#/$defs/Order"}}}`
+	)
+	tests := []struct {
+		name     string
+		inline   func(*entity.JSONSchema) error
+		expected string
+	}{
+		{"InlineRefs", transform.InlineRefs, `{"allOf": [` + order + `], "$defs": {"Order": ` + order + `}}`},
+		// Order is referenced twice
+		{"InlineSingleRefs", transform.InlineSingleRefs, `{"$ref": "#/$defs/Order", "$defs": {"Order": ` + order + `}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := unmarshalSchema(t, rootRefSchema)
+			require.NoError(t, tt.inline(schema))
+			actual, err := json.Marshal(schema)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+
+	// Definition, that can not be merged into root, is kept
+	schema := unmarshalSchema(t, `{"$ref": "#/$defs/Id", "$defs": {"Id": {"type": "string"}}}`)
+	require.NoError(t, transform.InlineRefs(schema))
+	actual, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"$ref": "#/$defs/Id", "$defs": {"Id": {"type": "string"}}}`, string(actual))
+}
@@ -0,0 +1,116 @@
+package transform
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/transform"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPruneDefinitions(t *testing.T) {
+	generator, err := jsonschema.FromTypeToJsonSchema(base.Settings{})
+	require.NoError(t, err)
+	// Only float value is left, but InnerSettings is still parsed type
+	generator.AfterParse = func(metadata *entity.JsonSchemaMetadata) error {
+		metadata.Root.Nodes = metadata.Root.Nodes[2:]
+		return nil
+	}
+	require.NoError(t, generator.Generate())
+	actual, err := generator.ToJson()
+	require.NoError(t, err)
+	require.Contains(t, string(actual), "InnerSettings")
+
+	generator.AfterConvert = transform.PruneDefinitions
+	require.NoError(t, generator.Generate())
+	actual, err = generator.ToJson()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://github.com/paulrozhkin/jsonschema/tests/base/Settings",
+		"type": "object",
+		"properties": {
+			"floatValue": {"type": "number"}
+		},
+		"required": ["floatValue"]
+	}`, string(actual))
+}
+
+func TestRefCounts(t *testing.T) {
+	schema := unmarshalSchema(t, countsSchema)
+
+	counts, err := transform.RefCounts(schema)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"Address": 1, "Money": 3, "Currency": 1}, counts)
+}
+
+func TestRefCountsOfRootRef(t *testing.T) {
+	schema := unmarshalSchema(t, `{"$ref": "#/$defs/Order", "$defs": {
+		"Order": {"type": "object", "properties": {"id": {"$ref": "#/$defs/Id"}}},
+		"Id": {"type": "string"},
+		"Unused": {"type": "object"}
+	}}`)
+
+	counts, err := transform.RefCounts(schema)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"Order": 1, "Id": 1}, counts)
+	require.NoError(t, transform.PruneDefinitions(schema))
+	require.Equal(t, []string{"Order", "Id"}, schema.Defs.Keys())
+}
+
+func TestInlineSingleRefs(t *testing.T) {
+	schema := unmarshalSchema(t, countsSchema)
+
+	require.NoError(t, transform.InlineSingleRefs(schema))
+	actual, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"address": {"type": "object", "properties": {"city": {"type": "string"}}},
+			"price": {"$ref": "#/$defs/Money"},
+			"discount": {"$ref": "#/$defs/Money"}
+		},
+		"$defs": {
+			"Money": {
+				"type": "object",
+				"properties": {
+					"amount": {"type": "number"},
+					"currency": {"type": "string", "enum": ["EUR", "USD"]},
+					"change": {"$ref": "#/$defs/Money"}
+				}
+			}
+		}
+	}`, string(actual))
+}
+
+const countsSchema = `{
+	"type": "object",
+	"properties": {
+		"address": {"$ref": "#/$defs/Address"},
+		"price": {"$ref": "#/$defs/Money"},
+		"discount": {"$ref": "#/$defs/Money"}
+	},
+	"$defs": {
+		"Address": {"type": "object", "properties": {"city": {"type": "string"}}},
+		"Money": {
+			"type": "object",
+			"properties": {
+				"amount": {"type": "number"},
+				"currency": {"$ref": "#/$defs/Currency"},
+				"change": {"$ref": "#/$defs/Money"}
+			}
+		},
+		"Currency": {"type": "string", "enum": ["EUR", "USD"]},
+		"Unused": {"type": "object", "properties": {"address": {"$ref": "#/$defs/Address"}}}
+	}
+}`
+
+func unmarshalSchema(t *testing.T, data string) *entity.JSONSchema {
+	t.Helper()
+	schema := new(entity.JSONSchema)
+	require.NoError(t, json.Unmarshal([]byte(data), schema))
+	return schema
+}
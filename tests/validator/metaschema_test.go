@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		draft  entity.DraftVersion
+		schema string
+		// errors contains expected pairs of instance location and keyword
+		errors [][2]string
+	}{
+		{
+			name:   "Valid 2020-12",
+			draft:  entity.Draft202012,
+			schema: `{"$defs": {"a": {"type": "string"}}, "properties": {"a": {"$ref": "#/$defs/a"}}, "prefixItems": [true]}`,
+		},
+		{
+			name:   "Invalid nested schema in 2020-12",
+			draft:  entity.Draft202012,
+			schema: `{"properties": {"a": {"type": "text", "minLength": -1}}}`,
+			errors: [][2]string{{"/properties/a/type", "anyOf"}, {"/properties/a/minLength", "minimum"}},
+		},
+		{
+			name:   "Invalid nested schema in 2019-09",
+			draft:  entity.Draft201909,
+			schema: `{"properties": {"a": {"required": "a"}}}`,
+			errors: [][2]string{{"/properties/a/required", "type"}},
+		},
+		{
+			name:   "Prefix items in draft-07",
+			draft:  entity.Draft07,
+			schema: `{"properties": {"a": {"prefixItems": [true]}}}`,
+			errors: [][2]string{{"/properties/a/prefixItems", "prefixItems"}},
+		},
+		{
+			name:   "Dynamic reference in draft-06",
+			draft:  entity.Draft06,
+			schema: `{"$dynamicRef": "#meta", "if": true}`,
+			errors: [][2]string{{"/$dynamicRef", "$dynamicRef"}, {"/if", "if"}},
+		},
+		{
+			name:   "Draft-04",
+			draft:  entity.Draft04,
+			schema: `{"id": "https://example.com/a", "maximum": 1, "exclusiveMaximum": 2}`,
+			errors: [][2]string{{"/exclusiveMaximum", "type"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.CheckSchema([]byte(tt.schema), tt.draft)
+			if len(tt.errors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var validationErrors validator.ValidationErrors
+			require.ErrorAs(t, err, &validationErrors)
+			actual := make([][2]string, len(validationErrors))
+			for i, validationError := range validationErrors {
+				actual[i] = [2]string{validationError.InstanceLocation, validationError.Keyword}
+			}
+			require.ElementsMatch(t, tt.errors, actual)
+		})
+	}
+}
+
+func TestCheckSchemaUnknownDraft(t *testing.T) {
+	require.ErrorContains(t, validator.CheckSchema([]byte(`{}`), "https://example.com/schema"), "unknown draft")
+}
+
+func TestDynamicReference(t *testing.T) {
+	// Extended list of strings overrides items of generic list
+	schema, err := validator.CompileJSON([]byte(`{
+		"$id": "https://example.com/strings",
+		"$ref": "list",
+		"$defs": {
+			"item": {"$dynamicAnchor": "item", "type": "string"},
+			"list": {
+				"$id": "list",
+				"type": "array",
+				"items": {"$dynamicRef": "#item"},
+				"$defs": {"item": {"$dynamicAnchor": "item"}}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	require.NoError(t, schema.ValidateBytes([]byte(`["a"]`)))
+
+	var validationErrors validator.ValidationErrors
+	require.ErrorAs(t, schema.ValidateBytes([]byte(`["a", 1]`)), &validationErrors)
+	require.Len(t, validationErrors, 1)
+	require.Equal(t, "/1", validationErrors[0].InstanceLocation)
+	require.Equal(t, "/$ref/items/$dynamicRef/type", validationErrors[0].KeywordLocation)
+}
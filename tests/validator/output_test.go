@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"encoding/json"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const outputSchema = `{
+	"$id": "https://example.com/polygon",
+	"$defs": {
+		"point": {
+			"type": "object",
+			"properties": {"x": {"type": "number"}, "y": {"type": "number"}},
+			"additionalProperties": false
+		}
+	},
+	"type": "array",
+	"items": {"$ref": "#/$defs/point"},
+	"minItems": 3
+}`
+
+const outputDocument = `[{"x": 2.5, "y": 1.3}, {"x": 1, "z": 6.7}]`
+
+func TestOutputFormats(t *testing.T) {
+	schema, err := validator.CompileJSON([]byte(outputSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		format   validator.OutputFormat
+		expected string
+	}{
+		{
+			format:   validator.OutputFlag,
+			expected: `{"valid": false}`,
+		},
+		{
+			format: validator.OutputBasic,
+			expected: `{
+				"valid": false,
+				"keywordLocation": "",
+				"absoluteKeywordLocation": "https://example.com/polygon#",
+				"instanceLocation": "",
+				"errors": [
+					{
+						"valid": false,
+						"keywordLocation": "/items/$ref/additionalProperties",
+						"absoluteKeywordLocation": "https://example.com/polygon#/$defs/point/additionalProperties",
+						"instanceLocation": "/1",
+						"error": "additional properties \"z\" are not allowed"
+					},
+					{
+						"valid": false,
+						"keywordLocation": "/minItems",
+						"absoluteKeywordLocation": "https://example.com/polygon#/minItems",
+						"instanceLocation": "",
+						"error": "array must have at least 3 items, but has 2"
+					}
+				]
+			}`,
+		},
+		{
+			format: validator.OutputDetailed,
+			expected: `{
+				"valid": false,
+				"keywordLocation": "",
+				"absoluteKeywordLocation": "https://example.com/polygon#",
+				"instanceLocation": "",
+				"errors": [
+					{
+						"valid": false,
+						"keywordLocation": "/items/$ref/additionalProperties",
+						"absoluteKeywordLocation": "https://example.com/polygon#/$defs/point/additionalProperties",
+						"instanceLocation": "/1",
+						"error": "additional properties \"z\" are not allowed"
+					},
+					{
+						"valid": false,
+						"keywordLocation": "/minItems",
+						"absoluteKeywordLocation": "https://example.com/polygon#/minItems",
+						"instanceLocation": "",
+						"error": "array must have at least 3 items, but has 2"
+					}
+				]
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			output, err := schema.EvaluateBytes([]byte(outputDocument), tt.format)
+			require.NoError(t, err)
+			actual, err := json.Marshal(output)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func TestVerboseOutput(t *testing.T) {
+	schema, err := validator.CompileJSON([]byte(outputSchema))
+	require.NoError(t, err)
+
+	output, err := schema.EvaluateBytes([]byte(`[{"x": 1}, {"y": 2}, {}]`), validator.OutputVerbose)
+	require.NoError(t, err)
+	require.True(t, output.Valid)
+	require.Empty(t, output.Errors)
+
+	// type, items and minItems of root schema
+	require.Len(t, output.Annotations, 3)
+	items := output.Annotations[1]
+	require.Equal(t, "/items", items.KeywordLocation)
+	require.Len(t, items.Annotations, 3)
+	require.Equal(t, "/2", items.Annotations[2].InstanceLocation)
+}
+
+func TestValidationErrorAbsoluteLocation(t *testing.T) {
+	schema, err := validator.CompileJSON([]byte(outputSchema))
+	require.NoError(t, err)
+
+	var validationErrors validator.ValidationErrors
+	require.ErrorAs(t, schema.ValidateBytes([]byte(outputDocument)), &validationErrors)
+	require.Equal(t, "https://example.com/polygon#/$defs/point/additionalProperties", validationErrors[0].AbsoluteKeywordLocation)
+}
@@ -0,0 +1,272 @@
+package validator
+
+import (
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"github.com/paulrozhkin/jsonschema/tests/additional"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestValidateKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		document string
+		// errors contains expected pairs of instance location and keyword location
+		errors [][2]string
+	}{
+		{
+			name:     "Type",
+			schema:   `{"type": "integer"}`,
+			document: `1.5`,
+			errors:   [][2]string{{"", "/type"}},
+		},
+		{
+			name:     "Integer with zero fraction",
+			schema:   `{"type": "integer"}`,
+			document: `1.0`,
+		},
+		{
+			name:     "Several types",
+			schema:   `{"type": ["string", "null"]}`,
+			document: `null`,
+		},
+		{
+			name:     "Enum and const",
+			schema:   `{"properties": {"a": {"enum": [1, "x"]}, "b": {"const": {"k": [1]}}}}`,
+			document: `{"a": 2, "b": {"k": [1.0]}}`,
+			errors:   [][2]string{{"/a", "/properties/a/enum"}},
+		},
+		{
+			name:     "Properties, required and additionalProperties",
+			schema:   `{"properties": {"a": {"type": "string"}}, "required": ["a", "b"], "additionalProperties": false}`,
+			document: `{"a": 1, "c": true}`,
+			errors: [][2]string{
+				{"/a", "/properties/a/type"},
+				{"", "/additionalProperties"},
+				{"", "/required"},
+			},
+		},
+		{
+			name:     "Pattern properties and property names",
+			schema:   `{"patternProperties": {"^x-": {"type": "string"}}, "propertyNames": {"maxLength": 3}, "additionalProperties": {"type": "integer"}}`,
+			document: `{"x-a": 1, "b": 2, "long": 3}`,
+			errors: [][2]string{
+				{"/x-a", "/patternProperties/^x-/type"},
+				{"/long", "/propertyNames/maxLength"},
+			},
+		},
+		{
+			name:     "Object size and dependencies",
+			schema:   `{"minProperties": 3, "dependentRequired": {"a": ["c"]}, "dependentSchemas": {"b": {"properties": {"a": {"type": "string"}}}}}`,
+			document: `{"a": 1, "b": 2}`,
+			errors: [][2]string{
+				{"", "/minProperties"},
+				{"", "/dependentRequired"},
+				{"/a", "/dependentSchemas/b/properties/a/type"},
+			},
+		},
+		{
+			name:     "Numeric constraints",
+			schema:   `{"items": {"multipleOf": 0.1, "minimum": 0, "exclusiveMaximum": 10}}`,
+			document: `[0.3, -1, 10, 0.35]`,
+			errors: [][2]string{
+				{"/1", "/items/minimum"},
+				{"/2", "/items/exclusiveMaximum"},
+				{"/3", "/items/multipleOf"},
+			},
+		},
+		{
+			name:     "Draft-04 exclusive maximum",
+			schema:   `{"$schema": "http://json-schema.org/draft-04/schema#", "maximum": 10, "exclusiveMaximum": true}`,
+			document: `10`,
+			errors:   [][2]string{{"", "/maximum"}},
+		},
+		{
+			name:     "String constraints",
+			schema:   `{"properties": {"a": {"minLength": 2, "maxLength": 3}, "b": {"pattern": "^[a-z]+$"}}}`,
+			document: `{"a": "абвг", "b": "abc1"}`,
+			errors: [][2]string{
+				{"/a", "/properties/a/maxLength"},
+				{"/b", "/properties/b/pattern"},
+			},
+		},
+		{
+			name:     "Array constraints",
+			schema:   `{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}, "minItems": 4, "uniqueItems": true, "contains": {"const": 5}}`,
+			document: `["a", 1, 1]`,
+			errors: [][2]string{
+				{"", "/contains"},
+				{"", "/minItems"},
+				{"", "/uniqueItems"},
+			},
+		},
+		{
+			name:     "Max contains",
+			schema:   `{"contains": {"type": "integer"}, "maxContains": 1}`,
+			document: `[1, 2]`,
+			errors:   [][2]string{{"", "/contains"}},
+		},
+		{
+			name:     "Tuple items of older drafts",
+			schema:   `{"items": [{"type": "string"}], "additionalItems": false}`,
+			document: `["a", 1]`,
+			errors:   [][2]string{{"/1", "/additionalItems"}},
+		},
+		{
+			name:     "Unevaluated items",
+			schema:   `{"allOf": [{"prefixItems": [{"type": "string"}]}], "unevaluatedItems": false}`,
+			document: `["a", 1]`,
+			errors:   [][2]string{{"/1", "/unevaluatedItems"}},
+		},
+		{
+			name:     "AllOf",
+			schema:   `{"allOf": [{"type": "integer"}, {"minimum": 5}]}`,
+			document: `3`,
+			errors:   [][2]string{{"", "/allOf/1/minimum"}},
+		},
+		{
+			name:     "AnyOf",
+			schema:   `{"anyOf": [{"type": "string"}, {"type": "boolean"}]}`,
+			document: `3`,
+			errors:   [][2]string{{"", "/anyOf"}},
+		},
+		{
+			name:     "OneOf",
+			schema:   `{"oneOf": [{"type": "integer"}, {"minimum": 0}]}`,
+			document: `3`,
+			errors:   [][2]string{{"", "/oneOf"}},
+		},
+		{
+			name:     "Not",
+			schema:   `{"not": {"type": "string"}}`,
+			document: `"a"`,
+			errors:   [][2]string{{"", "/not"}},
+		},
+		{
+			name:     "If then else",
+			schema:   `{"if": {"properties": {"kind": {"const": "a"}}}, "then": {"required": ["a"]}, "else": {"required": ["b"]}}`,
+			document: `{"kind": "a", "b": 1}`,
+			errors:   [][2]string{{"", "/then/required"}},
+		},
+		{
+			name:     "Recursive reference",
+			schema:   `{"$defs": {"node": {"properties": {"value": {"type": "integer"}, "next": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`,
+			document: `{"value": 1, "next": {"value": 2, "next": {"value": "3"}}}`,
+			errors:   [][2]string{{"/next/next/value", "/$ref/properties/next/$ref/properties/next/$ref/properties/value/type"}},
+		},
+		{
+			name:     "Reference by id and anchor",
+			schema:   `{"$id": "https://example.com/root.json", "$defs": {"a": {"$id": "a.json", "type": "string"}, "b": {"$anchor": "b", "type": "integer"}}, "properties": {"a": {"$ref": "a.json"}, "b": {"$ref": "#b"}}}`,
+			document: `{"a": 1, "b": "x"}`,
+			errors: [][2]string{
+				{"/a", "/properties/a/$ref/type"},
+				{"/b", "/properties/b/$ref/type"},
+			},
+		},
+		{
+			name:     "False schema",
+			schema:   `{"properties": {"a": false}}`,
+			document: `{"a": 1}`,
+			errors:   [][2]string{{"/a", "/properties/a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := validator.CompileJSON([]byte(tt.schema))
+			require.NoError(t, err)
+
+			err = schema.ValidateBytes([]byte(tt.document))
+			if len(tt.errors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var validationErrors validator.ValidationErrors
+			require.ErrorAs(t, err, &validationErrors)
+			actual := make([][2]string, len(validationErrors))
+			for i, validationError := range validationErrors {
+				actual[i] = [2]string{validationError.InstanceLocation, validationError.KeywordLocation}
+				require.True(t, strings.HasSuffix(validationError.KeywordLocation, validationError.Keyword),
+					"keyword %s of %s", validationError.Keyword, validationError.KeywordLocation)
+			}
+			require.ElementsMatch(t, tt.errors, actual)
+		})
+	}
+}
+
+func TestValidateEntitySchema(t *testing.T) {
+	jsonSchema := base.ExpectedSettingsJsonSchema()
+	jsonSchema.AdditionalProperties = entity.NewAdditionalPropertiesBool(false)
+	schema, err := validator.Compile(jsonSchema)
+	require.NoError(t, err)
+
+	valid := base.Settings{ValInnerSettings: additional.InnerSettings{StringValue: "a", IntValue: 10}}
+	require.NoError(t, schema.Validate(valid))
+
+	invalid := base.Settings{
+		ValInnerSettings: additional.InnerSettings{IntValue: 11},
+		RefInnerSettings: &additional.InnerSettings{IntValue: -1},
+	}
+	err = schema.Validate(invalid)
+	var validationErrors validator.ValidationErrors
+	require.ErrorAs(t, err, &validationErrors)
+	require.Len(t, validationErrors, 2)
+	require.Equal(t, "/refInnerSettings/intValue", validationErrors[0].InstanceLocation)
+	require.Equal(t, "/properties/refInnerSettings/$ref/properties/intValue/minimum", validationErrors[0].KeywordLocation)
+	require.Equal(t, "minimum", validationErrors[0].Keyword)
+	require.Equal(t, "/valInnerSettings/intValue", validationErrors[1].InstanceLocation)
+	require.Equal(t, "maximum", validationErrors[1].Keyword)
+
+	err = schema.ValidateReader(strings.NewReader(`{"valInnerSettings": {}, "floatValue": 1, "other": 1}`))
+	require.ErrorAs(t, err, &validationErrors)
+	require.Len(t, validationErrors, 2)
+	require.Equal(t, "required", validationErrors[0].Keyword)
+	require.Equal(t, "/valInnerSettings", validationErrors[0].InstanceLocation)
+	require.Equal(t, "additionalProperties", validationErrors[1].Keyword)
+}
+
+func TestValidateFormat(t *testing.T) {
+	const schemaJSON = `{"properties": {"date": {"format": "date"}, "email": {"format": "email"}}}`
+	document := []byte(`{"date": "2024-13-01", "email": "not email"}`)
+
+	schema, err := validator.CompileJSON([]byte(schemaJSON))
+	require.NoError(t, err)
+	require.NoError(t, schema.ValidateBytes(document))
+
+	schema, err = validator.CompileJSON([]byte(schemaJSON), validator.WithFormatAssertion())
+	require.NoError(t, err)
+	var validationErrors validator.ValidationErrors
+	require.ErrorAs(t, schema.ValidateBytes(document), &validationErrors)
+	require.Len(t, validationErrors, 2)
+	require.NoError(t, schema.ValidateBytes([]byte(`{"date": "2024-12-01", "email": "a@b.c"}`)))
+}
+
+func TestCompileInvalidSchema(t *testing.T) {
+	_, err := validator.CompileJSON([]byte(`{"$ref": "#/$defs/missing"}`))
+	require.ErrorContains(t, err, "unresolved reference #/$defs/missing")
+
+	_, err = validator.CompileJSON([]byte(`{"pattern": "("}`))
+	require.ErrorContains(t, err, "invalid pattern")
+}
+
+func TestCompileReferenceCycle(t *testing.T) {
+	tests := []string{
+		`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}}`,
+		`{"anyOf": [{"type": "string"}, {"$ref": "#"}]}`,
+		`{"properties": {"node": {"allOf": [{"$ref": "#/properties/node"}]}}}`,
+	}
+	for _, tt := range tests {
+		_, err := validator.CompileJSON([]byte(tt))
+		require.ErrorIs(t, err, validator.ErrReferenceCycle, tt)
+	}
+
+	// Recursion into nested instances ends with the instance
+	schema, err := validator.CompileJSON([]byte(`{"type": "object",
+		"properties": {"child": {"$ref": "#"}}, "additionalProperties": false}`))
+	require.NoError(t, err)
+	require.NoError(t, schema.ValidateBytes([]byte(`{"child": {"child": {}}}`)))
+}
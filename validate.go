@@ -0,0 +1,135 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paulrozhkin/jsonschema/pkg/converter"
+	"github.com/paulrozhkin/jsonschema/pkg/entity"
+	"github.com/paulrozhkin/jsonschema/pkg/parser"
+	"github.com/paulrozhkin/jsonschema/pkg/validator"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var ErrNilValue = errors.New("value for validation is nil")
+
+// FieldError is a validation error with path of Go struct fields
+type FieldError struct {
+	// FieldPath is path of Go fields to the invalid value, e.g. Settings.ValInnerSettings.IntValue
+	FieldPath string
+	*validator.ValidationError
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.FieldPath, e.Message, e.KeywordLocation)
+}
+
+// FieldErrors contains all validation errors of value
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "jsonschema validation failed:\n" + strings.Join(messages, "\n")
+}
+
+// typeValidator is a compiled schema of Go type with metadata used to map errors to Go fields
+type typeValidator struct {
+	schema   *validator.Schema
+	metadata *entity.JsonSchemaMetadata
+}
+
+// validators caches typeValidator by reflect.Type
+var validators sync.Map
+
+// Validate validates Go value against schema of its type generated by ReflectParser. Schema is generated once
+// for every type. Returned error is FieldErrors, if value is invalid.
+func Validate(v any) error {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return ErrNilValue
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	typeValidator, err := validatorOfType(typ)
+	if err != nil {
+		return err
+	}
+
+	err = typeValidator.schema.Validate(v)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	fieldErrors := make(FieldErrors, len(validationErrors))
+	for i, validationError := range validationErrors {
+		fieldErrors[i] = &FieldError{
+			FieldPath:       typeValidator.fieldPath(validationError.InstanceLocation),
+			ValidationError: validationError,
+		}
+	}
+	return fieldErrors
+}
+
+func validatorOfType(typ reflect.Type) (*typeValidator, error) {
+	if cached, ok := validators.Load(typ); ok {
+		return cached.(*typeValidator), nil
+	}
+
+	result := &typeValidator{}
+	generator := DefaultGenerator()
+	generator.Parser = parser.NewReflectParser(reflect.Zero(typ).Interface())
+	generator.AfterParse = func(metadata *entity.JsonSchemaMetadata) error {
+		result.metadata = metadata
+		return nil
+	}
+	if err := generator.Generate(); err != nil {
+		return nil, fmt.Errorf("generate schema for %s: %w", typ, err)
+	}
+
+	schema, err := validator.Compile(generator.jsonSchema)
+	if err != nil {
+		return nil, fmt.Errorf("compile schema for %s: %w", typ, err)
+	}
+	result.schema = schema
+
+	cached, _ := validators.LoadOrStore(typ, result)
+	return cached.(*typeValidator), nil
+}
+
+// fieldPath converts JSON Pointer of value to path of Go fields. Tokens, that do not match fields,
+// are kept as is.
+func (v *typeValidator) fieldPath(instanceLocation string) string {
+	root := v.metadata.Root
+	path := []string{root.TypeName}
+	if instanceLocation == "" {
+		return root.TypeName
+	}
+
+	current := root
+	for _, token := range strings.Split(instanceLocation[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		var field *entity.DataTypeMetadata
+		if current != nil {
+			for _, node := range current.Nodes {
+				if converter.FieldName(node) == token {
+					field = node
+					break
+				}
+			}
+		}
+		if field == nil {
+			path = append(path, token)
+			current = nil
+			continue
+		}
+		path = append(path, field.FieldName)
+		current = field.Ref
+	}
+	return strings.Join(path, ".")
+}
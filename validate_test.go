@@ -0,0 +1,35 @@
+package jsonschema
+
+import (
+	"github.com/paulrozhkin/jsonschema/tests/additional"
+	"github.com/paulrozhkin/jsonschema/tests/base"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValidateValue(t *testing.T) {
+	valid := base.Settings{ValInnerSettings: additional.InnerSettings{IntValue: 5}}
+	require.NoError(t, Validate(valid))
+	require.NoError(t, Validate(&valid))
+
+	invalid := &base.Settings{
+		ValInnerSettings: additional.InnerSettings{IntValue: 11},
+		RefInnerSettings: &additional.InnerSettings{IntValue: -1},
+	}
+	err := Validate(invalid)
+	var fieldErrors FieldErrors
+	require.ErrorAs(t, err, &fieldErrors)
+	require.Len(t, fieldErrors, 2)
+
+	require.Equal(t, "Settings.RefInnerSettings.IntValue", fieldErrors[0].FieldPath)
+	require.Equal(t, "/refInnerSettings/intValue", fieldErrors[0].InstanceLocation)
+	require.Equal(t, "minimum", fieldErrors[0].Keyword)
+
+	require.Equal(t, "Settings.ValInnerSettings.IntValue", fieldErrors[1].FieldPath)
+	require.Equal(t, "/valInnerSettings/intValue", fieldErrors[1].InstanceLocation)
+	require.Equal(t, "maximum", fieldErrors[1].Keyword)
+}
+
+func TestValidateNil(t *testing.T) {
+	require.ErrorIs(t, Validate(nil), ErrNilValue)
+}